@@ -3,13 +3,19 @@ package main
 import (
 	"context"
 	"errors"
+	"fmt"
 	"log"
+	"math/rand/v2"
 	"net/http"
 	"os"
 	"os/signal"
+	"strconv"
+	"strings"
 	"syscall"
 	"time"
+	_ "time/tzdata" // user timezones, runtime image has no zoneinfo
 
+	"avito/internal/domain"
 	"avito/internal/service"
 	"avito/internal/storage/pgx"
 	transport "avito/internal/transport/http"
@@ -39,19 +45,43 @@ func main() {
 		log.Fatalf("db ping failed: %v", err)
 	}
 
+	selector, err := newReviewerSelector(st)
+	if err != nil {
+		log.Fatalf("failed to init reviewer selector: %v", err)
+	}
+
+	seeds, err := newSeedSource()
+	if err != nil {
+		log.Fatalf("failed to init seed source: %v", err)
+	}
+
 	svc := service.NewService(
-		st, // TeamStorage
-		st, // UserStorage
-		st, // PullRequestStorage
-		st, // txManager
+		st,       // TeamStorage
+		st,       // UserStorage
+		st,       // PullRequestStorage
+		st,       // txManager
+		selector, // ReviewerSelector
+		seeds,    // seeds of reviewer selection
 	)
 
 	router := transport.NewHandler(
-		svc, // TeamsService
-		svc, // UsersService
-		svc, // PullRequestsService
+		svc,                      // TeamsService
+		svc,                      // UsersService
+		svc,                      // PullRequestsService
+		os.Getenv("ADMIN_TOKEN"), // token of admin only requests, empty forbids them
 	)
 
+	backfillInterval := time.Minute
+	if raw := os.Getenv("BACKFILL_INTERVAL"); raw != "" {
+		backfillInterval, err = time.ParseDuration(raw)
+		if err != nil {
+			log.Fatalf("invalid BACKFILL_INTERVAL: %v", err)
+		}
+	}
+	if backfillInterval > 0 {
+		go runBackfill(ctx, svc, backfillInterval)
+	}
+
 	srv := &http.Server{
 		Addr:         addr,
 		Handler:      router.Routes(),
@@ -79,3 +109,105 @@ func main() {
 		log.Println("HTTP server gracefully stopped")
 	}
 }
+
+// runBackfill periodically tops up reviewers of understaffed PRs until ctx is done.
+func runBackfill(ctx context.Context, svc *service.Service, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			backfills, err := svc.BackfillReviewers(ctx, "")
+			if err != nil {
+				log.Printf("backfill failed: %v", err)
+				continue
+			}
+			staffed := 0
+			for _, backfill := range backfills {
+				if backfill.Err != nil {
+					log.Printf("backfill of pull request %q failed: %v", backfill.PullRequest.ID, backfill.Err)
+					continue
+				}
+				staffed++
+			}
+			if staffed > 0 {
+				log.Printf("backfill: %d pull requests got new reviewers", staffed)
+			}
+		}
+	}
+}
+
+// newReviewerSelector builds selector from env:
+//
+//	REVIEWER_STRATEGY     - default strategy for teams without strategy in settings (RANDOM if empty)
+//	REVIEWER_WEIGHTS      - weights for WEIGHTED strategy, "u1=3,u2=1"
+//	PAIR_DIVERSITY_WINDOW - how many last assignments of the author PAIR_DIVERSITY looks at (20 if empty)
+func newReviewerSelector(st *pgx.Storage) (service.ReviewerSelector, error) {
+	weights := make(map[string]int)
+	for userID, raw := range parseKeyValues(os.Getenv("REVIEWER_WEIGHTS")) {
+		weight, err := strconv.Atoi(raw)
+		if err != nil || weight < 0 {
+			return nil, fmt.Errorf("invalid weight %q for user %q", raw, userID)
+		}
+		weights[userID] = weight
+	}
+
+	window := 0
+	if raw := os.Getenv("PAIR_DIVERSITY_WINDOW"); raw != "" {
+		var err error
+		window, err = strconv.Atoi(raw)
+		if err != nil || window <= 0 {
+			return nil, fmt.Errorf("invalid PAIR_DIVERSITY_WINDOW %q", raw)
+		}
+	}
+
+	selectors := map[domain.SelectionStrategy]service.ReviewerSelector{
+		domain.StrategyRandom:        service.NewRandomSelector(),
+		domain.StrategyRoundRobin:    service.NewRoundRobinSelector(),
+		domain.StrategyLeastLoaded:   service.NewLeastLoadedSelector(st),
+		domain.StrategyWeighted:      service.NewWeightedSelector(weights, 1),
+		domain.StrategyPairDiversity: service.NewPairDiversitySelector(st, window),
+	}
+
+	strategy := domain.StrategyRandom
+	if raw := os.Getenv("REVIEWER_STRATEGY"); raw != "" {
+		strategy = domain.SelectionStrategy(strings.ToUpper(raw))
+	}
+
+	def, ok := selectors[strategy]
+	if !ok {
+		return nil, fmt.Errorf("unknown reviewer strategy %q", strategy)
+	}
+
+	return service.NewStrategySelector(def, selectors), nil
+}
+
+// newSeedSource returns source of selection seeds: fixed sequence when REVIEWER_SEED
+// is set (tests, incident replay), random otherwise.
+func newSeedSource() (rand.Source, error) {
+	raw := os.Getenv("REVIEWER_SEED")
+	if raw == "" {
+		return nil, nil
+	}
+	seed, err := strconv.ParseUint(raw, 10, 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid REVIEWER_SEED %q", raw)
+	}
+	return rand.NewPCG(seed, 0), nil
+}
+
+// parseKeyValues parses "k1=v1,k2=v2", empty pairs are skipped.
+func parseKeyValues(raw string) map[string]string {
+	out := make(map[string]string)
+	for _, pair := range strings.Split(raw, ",") {
+		key, value, ok := strings.Cut(strings.TrimSpace(pair), "=")
+		if !ok || key == "" {
+			continue
+		}
+		out[strings.TrimSpace(key)] = strings.TrimSpace(value)
+	}
+	return out
+}
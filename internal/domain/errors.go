@@ -7,6 +7,16 @@ var (
 	ErrPRExists    = errors.New("pr already exists")
 	ErrPRMerged    = errors.New("pr merged")
 	ErrNotAssigned = errors.New("reviewer not assigned")
-	ErrNoCandidate = errors.New("no candidate")
-	ErrNotFound    = errors.New("not found")
+
+	ErrAlreadyAssigned     = errors.New("reviewer already assigned")
+	ErrReviewerUnavailable = errors.New("reviewer unavailable")
+	ErrNoCandidate         = errors.New("no candidate")
+	ErrNotFound            = errors.New("not found")
+
+	ErrMandatoryReviewer = errors.New("reviewer is mandatory")
+	ErrSeniorityPolicy   = errors.New("seniority policy not satisfied")
+	ErrNotApproved       = errors.New("pr not approved")
+	ErrInvalidStatus     = errors.New("pr status does not allow it")
+
+	ErrInvalidInput = errors.New("invalid input")
 )
@@ -1,12 +1,99 @@
 package domain
 
-import "time"
+import (
+	"sync"
+	"time"
+)
 
 type User struct {
-	ID       string
-	Name     string
-	TeamName string
-	IsActive bool
+	ID             string
+	Name           string
+	TeamName       string
+	IsActive       bool
+	MaxOpenReviews *int          // nil - no limit
+	Timezone       string        // IANA name, empty - UTC
+	WorkingHours   *WorkingHours // nil - always available
+	Tags           []string      // expertise, lower case
+	Seniority      Seniority     // empty - unknown
+}
+
+// WorkingHours window in minutes from midnight in the user's timezone,
+// Start is inclusive and End is exclusive. End before Start means
+// the window goes over midnight.
+type WorkingHours struct {
+	Start int
+	End   int
+}
+
+func (h WorkingHours) Contains(minute int) bool {
+	if h.Start <= h.End {
+		return minute >= h.Start && minute < h.End
+	}
+	return minute >= h.Start || minute < h.End
+}
+
+// IsWorkingAt users without working hours or with unknown timezone are always working.
+func (u User) IsWorkingAt(t time.Time) bool {
+	if u.WorkingHours == nil {
+		return true
+	}
+
+	loc := location(u.Timezone)
+	if loc == nil {
+		return true
+	}
+
+	local := t.In(loc)
+	return u.WorkingHours.Contains(local.Hour()*60 + local.Minute())
+}
+
+// locations caches time.LoadLocation by timezone name, nil value - unknown timezone.
+var locations sync.Map
+
+func location(name string) *time.Location {
+	if loc, ok := locations.Load(name); ok {
+		return loc.(*time.Location)
+	}
+
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		loc = nil
+	}
+	locations.Store(name, loc)
+	return loc
+}
+
+type Seniority string
+
+const (
+	SeniorityJunior Seniority = "JUNIOR"
+	SeniorityMiddle Seniority = "MIDDLE"
+	SenioritySenior Seniority = "SENIOR"
+	SeniorityLead   Seniority = "LEAD"
+)
+
+func (s Seniority) IsValid() bool {
+	switch s {
+	case SeniorityJunior, SeniorityMiddle, SenioritySenior, SeniorityLead:
+		return true
+	}
+	return false
+}
+
+// AtLeastSenior senior or lead.
+func (s Seniority) AtLeastSenior() bool {
+	return s == SenioritySenior || s == SeniorityLead
+}
+
+// Absence user is unavailable for review from StartDate to EndDate inclusive (UTC dates).
+type Absence struct {
+	ID          int64
+	UserID      string
+	StartDate   time.Time
+	EndDate     time.Time
+	Reason      string
+	CreatedAt   time.Time
+	CancelledAt *time.Time
 }
 
 type Team struct {
@@ -15,19 +102,328 @@ type Team struct {
 	Members []User
 }
 
+// TeamSettings reviewer policy of the team.
+// Empty Strategy means the default strategy of the service.
+// BackupTeams are asked in order when team has less than MinReviewers candidates.
+// AllowPartial allows to create PR with less than MinReviewers reviewers
+// when even backup teams have not enough candidates.
+// PreferWorkingHours candidates outside their working hours are chosen
+// only when there are not enough working ones.
+// MandatoryReviewers are assigned to every PR of the team before others
+// when they are active, not absent and not the author.
+// RequireSenior at least one reviewer is senior or lead.
+// JuniorsNotAlone a junior reviewer needs a reviewer who is not a junior next to them,
+// users of unknown seniority are not juniors.
+// RequiredApprovals PR is merged when it has this many APPROVED reviews and no
+// CHANGES_REQUESTED ones, 0 disables the rule.
+type TeamSettings struct {
+	TeamName           string
+	MinReviewers       int
+	MaxReviewers       int
+	Strategy           SelectionStrategy
+	AllowPartial       bool
+	BackupTeams        []string
+	PreferWorkingHours bool
+	MandatoryReviewers []string
+	RequireSenior      bool
+	JuniorsNotAlone    bool
+	RequiredApprovals  int
+}
+
+// TeamSettingsUpdate nil fields are left unchanged.
+type TeamSettingsUpdate struct {
+	MinReviewers       *int
+	MaxReviewers       *int
+	Strategy           *SelectionStrategy
+	AllowPartial       *bool
+	BackupTeams        *[]string
+	PreferWorkingHours *bool
+	MandatoryReviewers *[]string
+	RequireSenior      *bool
+	JuniorsNotAlone    *bool
+	RequiredApprovals  *int
+}
+
+// CodeOwnersRule files matching Pattern are owned by Users and by members of Teams.
+// When several rules match a file, the last one wins.
+type CodeOwnersRule struct {
+	Pattern string
+	Users   []string
+	Teams   []string
+}
+
 type PullRequestStatus string
 
 const (
-	PRStatusOpen   PullRequestStatus = "OPEN"
-	PRStatusMerged PullRequestStatus = "MERGED"
+	PRStatusOpen           PullRequestStatus = "OPEN"
+	PRStatusNeedsReviewers PullRequestStatus = "NEEDS_REVIEWERS" // open, but reviewers are at capacity
+	PRStatusMerged         PullRequestStatus = "MERGED"
+	PRStatusDraft          PullRequestStatus = "DRAFT"  // reviewers are assigned when it becomes ready
+	PRStatusClosed         PullRequestStatus = "CLOSED" // abandoned, may be reopened
+)
+
+func (s PullRequestStatus) IsValid() bool {
+	switch s {
+	case PRStatusOpen, PRStatusNeedsReviewers, PRStatusMerged, PRStatusDraft, PRStatusClosed:
+		return true
+	}
+	return false
+}
+
+// IsOpen PR is not finished yet and keeps its reviewers busy.
+func (s PullRequestStatus) IsOpen() bool {
+	return s == PRStatusOpen || s == PRStatusNeedsReviewers
+}
+
+type SelectionStrategy string
+
+const (
+	StrategyRandom      SelectionStrategy = "RANDOM"
+	StrategyRoundRobin  SelectionStrategy = "ROUND_ROBIN"
+	StrategyLeastLoaded SelectionStrategy = "LEAST_LOADED"
+	StrategyWeighted    SelectionStrategy = "WEIGHTED"
+	// StrategyPairDiversity avoids reviewers recently paired with the author.
+	StrategyPairDiversity SelectionStrategy = "PAIR_DIVERSITY"
 )
 
+func (s SelectionStrategy) IsValid() bool {
+	switch s {
+	case StrategyRandom, StrategyRoundRobin, StrategyLeastLoaded, StrategyWeighted, StrategyPairDiversity:
+		return true
+	}
+	return false
+}
+
 type PullRequest struct {
-	ID                string
-	Name              string
-	AuthorID          string
-	Status            PullRequestStatus
-	AssignedReviewers []string
-	CreatedAt         *time.Time
-	MergedAt          *time.Time
+	ID                 string
+	Name               string
+	Description        string
+	URL                string   // external link, e.g. to the code hosting
+	Labels             []string // lower cased, see PullRequestUpdate
+	AuthorID           string
+	Status             PullRequestStatus
+	AssignedReviewers  []string
+	FallbackReviewers  []string // subset of AssignedReviewers taken from backup teams
+	MandatoryReviewers []string // subset of AssignedReviewers required by the team policy
+	CreatedAt          *time.Time
+	MergedAt           *time.Time
+
+	// Reviews last verdicts by reviewer, missing reviewers are PENDING, see ReviewOf.
+	Reviews map[string]Review
+	// MergeOverride is set when PR was merged bypassing the approval rule of the team.
+	MergeOverride *MergeOverride
+	// ClosedAt and ClosedFrom (the status before closing) are set while PR is CLOSED.
+	ClosedAt   *time.Time
+	ClosedFrom PullRequestStatus
+
+	// RequiredTags and MatchedTags (required tags each reviewer has) are known on creation only.
+	RequiredTags []string
+	MatchedTags  map[string][]string
+}
+
+// ReviewOf returns the last verdict of the reviewer.
+func (pr PullRequest) ReviewOf(userID string) Review {
+	if review, ok := pr.Reviews[userID]; ok && review.State != "" {
+		return review
+	}
+	return Review{State: ReviewPending}
+}
+
+// PullRequestFilter empty fields do not filter. TeamName is the team of the author,
+// PR has all of Labels. Time ranges include From and exclude To.
+// Pull requests are ordered by (CreatedAt, ID), After is the last one of the previous page.
+type PullRequestFilter struct {
+	Statuses    []PullRequestStatus
+	AuthorID    string
+	ReviewerID  string
+	TeamName    string
+	Labels      []string
+	CreatedFrom *time.Time
+	CreatedTo   *time.Time
+	MergedFrom  *time.Time
+	MergedTo    *time.Time
+	Limit       int
+	After       *PullRequestCursor
+}
+
+// PullRequestCursor position in the list ordered by (CreatedAt, ID).
+type PullRequestCursor struct {
+	CreatedAt time.Time
+	ID        string
+}
+
+// PullRequestPage Next is nil on the last page.
+type PullRequestPage struct {
+	PullRequests []PullRequest
+	Next         *PullRequestCursor
+}
+
+// PullRequestUpdate nil fields are left unchanged, empty Description and URL clear them,
+// Labels replace all labels of PR.
+type PullRequestUpdate struct {
+	Name        *string
+	Description *string
+	URL         *string
+	Labels      *[]string
+}
+
+// MergeOverride who merged PR without required approvals and why.
+type MergeOverride struct {
+	By     string
+	Reason string
+}
+
+type ReviewState string
+
+const (
+	ReviewPending          ReviewState = "PENDING"
+	ReviewApproved         ReviewState = "APPROVED"
+	ReviewChangesRequested ReviewState = "CHANGES_REQUESTED"
+	ReviewCommented        ReviewState = "COMMENTED"
+)
+
+// IsVerdict the state may be submitted by a reviewer.
+func (s ReviewState) IsVerdict() bool {
+	switch s {
+	case ReviewApproved, ReviewChangesRequested, ReviewCommented:
+		return true
+	}
+	return false
+}
+
+// Review UpdatedAt is nil while the review is PENDING.
+type Review struct {
+	State     ReviewState
+	UpdatedAt *time.Time
+}
+
+// PullRequestCreate ChangedFiles are slash separated paths from the repository root,
+// at least one owner of them is assigned when the author's team has code owners.
+// Reviewers covering RequiredTags are preferred.
+// Draft PR gets no reviewers until it is marked ready.
+type PullRequestCreate struct {
+	ID           string
+	Name         string
+	AuthorID     string
+	ChangedFiles []string
+	RequiredTags []string
+	Draft        bool
+}
+
+type AssignmentAction string
+
+const (
+	AssignmentCreate   AssignmentAction = "CREATE"
+	AssignmentReassign AssignmentAction = "REASSIGN"
+	AssignmentBackfill AssignmentAction = "BACKFILL"
+)
+
+// AssignmentAudit how reviewers of one pull request were chosen by one operation.
+// Steps are selector calls in order, all of them used the random source
+// seeded with Seed and PullRequestID.
+type AssignmentAudit struct {
+	ID            int64
+	PullRequestID string
+	Action        AssignmentAction
+	Seed          uint64
+	Steps         []SelectionStep
+	CreatedAt     time.Time
+}
+
+// SelectionStep one selector call: Chosen are Quantity or less of Candidates.
+// Strategy is resolved like AssignmentDecision.Strategy, empty means the default
+// strategy of the service at replay time (steps recorded before it was resolved).
+// Inputs is nil in steps recorded before inputs were, they are replayed with the current state.
+type SelectionStep struct {
+	TeamName   string
+	Strategy   SelectionStrategy
+	Candidates []string
+	Quantity   int
+	Chosen     []string
+	Inputs     *SelectionInputs
+}
+
+// SelectionInputs outside state the strategy used besides candidates and the random source,
+// only the fields of the strategy are set.
+type SelectionInputs struct {
+	Loads   map[string]int // LEAST_LOADED: open reviews of candidates
+	Pairs   map[string]int // PAIR_DIVERSITY: recent reviews of candidates for the author
+	Weights map[string]int // WEIGHTED: weights of candidates
+	Last    string         // ROUND_ROBIN: the last assigned user of the team before the step
+}
+
+// AssignmentDecision why reviewers of one pull request were chosen by one operation:
+// members of every team asked, eligible or excluded with a reason, and the reason
+// every reviewer was chosen for. Strategy is the one resolved for the author's team.
+type AssignmentDecision struct {
+	ID            int64
+	PullRequestID string
+	Action        AssignmentAction
+	Strategy      SelectionStrategy
+	Teams         []TeamCandidates
+	Reviewers     []ChosenReviewer
+	CreatedAt     time.Time
+}
+
+// TeamCandidates Eligible includes chosen reviewers of the team.
+type TeamCandidates struct {
+	TeamName string
+	Eligible []string
+	Excluded []Exclusion
+}
+
+type ExclusionReason string
+
+const (
+	ExcludedAuthor          ExclusionReason = "AUTHOR"
+	ExcludedInactive        ExclusionReason = "INACTIVE"
+	ExcludedAbsent          ExclusionReason = "ABSENT"
+	ExcludedAtCapacity      ExclusionReason = "AT_CAPACITY"
+	ExcludedAlreadyAssigned ExclusionReason = "ALREADY_ASSIGNED"
+	ExcludedReplaced        ExclusionReason = "REPLACED"
+)
+
+type Exclusion struct {
+	UserID string
+	Reason ExclusionReason
+}
+
+type ChoiceReason string
+
+const (
+	ChosenMandatory ChoiceReason = "MANDATORY"
+	ChosenCodeOwner ChoiceReason = "CODE_OWNER"
+	ChosenTags      ChoiceReason = "TAGS"
+	ChosenSeniority ChoiceReason = "SENIORITY"
+	ChosenStrategy  ChoiceReason = "STRATEGY"
+)
+
+type ChosenReviewer struct {
+	UserID     string
+	TeamName   string
+	Reason     ChoiceReason
+	IsFallback bool
+}
+
+// PullRequestPreview reviewers the pull request would get if it was created now.
+type PullRequestPreview struct {
+	PullRequest PullRequest
+	Decision    AssignmentDecision
+}
+
+// Backfill result of topping up reviewers of one pull request.
+// Err is set when the pull request could not be topped up in the batch mode.
+type Backfill struct {
+	PullRequest    PullRequest
+	AddedReviewers []string
+	Err            error
+}
+
+// Reassignment one replaced reviewer of a pull request.
+// Empty NewReviewerID means there was no candidate and old reviewer stays assigned.
+type Reassignment struct {
+	PullRequestID string
+	OldReviewerID string
+	NewReviewerID string
+	IsFallback    bool
 }
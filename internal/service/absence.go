@@ -0,0 +1,80 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"avito/internal/domain"
+)
+
+func (s *Service) AddAbsence(ctx context.Context, absence domain.Absence) (domain.Absence, error) {
+	now := time.Now().UTC()
+	today := now.Truncate(24 * time.Hour)
+
+	if absence.EndDate.Before(absence.StartDate) {
+		return domain.Absence{}, fmt.Errorf("%w: end_date must not be before start_date", domain.ErrInvalidInput)
+	}
+	if absence.EndDate.Before(today) {
+		return domain.Absence{}, fmt.Errorf("%w: absence is already over", domain.ErrInvalidInput)
+	}
+
+	err := s.tx.WithTx(ctx, func(ctx context.Context) error {
+		if _, err := s.userStore.GetUserByID(ctx, absence.UserID); err != nil {
+			return err
+		}
+
+		absence.CreatedAt = now
+		absence.CancelledAt = nil
+
+		id, err := s.userStore.AddAbsence(ctx, absence)
+		if err != nil {
+			return err
+		}
+		absence.ID = id
+		return nil
+	})
+	if err != nil {
+		return domain.Absence{}, err
+	}
+
+	return absence, nil
+}
+
+func (s *Service) ListAbsences(ctx context.Context, userID string) ([]domain.Absence, error) {
+	if _, err := s.userStore.GetUserByID(ctx, userID); err != nil {
+		return nil, err
+	}
+	return s.userStore.ListAbsences(ctx, userID)
+}
+
+// CancelAbsence is idempotent, cancelled absence is returned as is.
+func (s *Service) CancelAbsence(ctx context.Context, absenceID int64) (domain.Absence, error) {
+	var result domain.Absence
+
+	err := s.tx.WithTx(ctx, func(ctx context.Context) error {
+		absence, err := s.userStore.GetAbsenceByID(ctx, absenceID)
+		if err != nil {
+			return err
+		}
+
+		if absence.CancelledAt != nil {
+			result = absence
+			return nil
+		}
+
+		now := time.Now().UTC()
+		if err := s.userStore.CancelAbsence(ctx, absenceID, now); err != nil {
+			return err
+		}
+
+		absence.CancelledAt = &now
+		result = absence
+		return nil
+	})
+	if err != nil {
+		return domain.Absence{}, err
+	}
+
+	return result, nil
+}
@@ -0,0 +1,95 @@
+package service
+
+import (
+	"avito/internal/domain"
+	"avito/internal/service/mocks"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestService_AddAbsence(t *testing.T) {
+	ctx := context.Background()
+	today := time.Now().UTC().Truncate(24 * time.Hour)
+
+	tests := []struct {
+		name    string
+		absence domain.Absence
+		wantErr error
+	}{
+		{
+			name:    "valid",
+			absence: domain.Absence{UserID: "u1", StartDate: today, EndDate: today.AddDate(0, 0, 7), Reason: "vacation"},
+		},
+		{
+			name:    "end_before_start",
+			absence: domain.Absence{UserID: "u1", StartDate: today.AddDate(0, 0, 7), EndDate: today},
+			wantErr: domain.ErrInvalidInput,
+		},
+		{
+			name:    "already_over",
+			absence: domain.Absence{UserID: "u1", StartDate: today.AddDate(0, 0, -7), EndDate: today.AddDate(0, 0, -1)},
+			wantErr: domain.ErrInvalidInput,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			userStore := mocks.NewUserStorage(t)
+
+			if tt.wantErr == nil {
+				userStore.
+					On("GetUserByID", ctx, "u1").
+					Return(&domain.User{ID: "u1"}, nil).Once()
+
+				userStore.
+					On("AddAbsence", ctx, mock.AnythingOfType("domain.Absence")).
+					Return(int64(42), nil).Once()
+			}
+
+			svc := NewService(mocks.NewTeamStorage(t), userStore, mocks.NewPullRequestStorage(t), &mockTxManager{}, NewRandomSelector(), nil)
+
+			got, err := svc.AddAbsence(ctx, tt.absence)
+			if tt.wantErr != nil {
+				require.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, int64(42), got.ID)
+			assert.False(t, got.CreatedAt.IsZero())
+		})
+	}
+}
+
+func TestService_CancelAbsence_Idempotent(t *testing.T) {
+	ctx := context.Background()
+
+	userStore := mocks.NewUserStorage(t)
+	absence := domain.Absence{ID: 1, UserID: "u1"}
+
+	userStore.
+		On("GetAbsenceByID", ctx, int64(1)).
+		Return(absence, nil).Once()
+
+	userStore.
+		On("CancelAbsence", ctx, int64(1), mock.AnythingOfType("time.Time")).
+		Return(nil).Once()
+
+	svc := NewService(mocks.NewTeamStorage(t), userStore, mocks.NewPullRequestStorage(t), &mockTxManager{}, NewRandomSelector(), nil)
+
+	got1, err := svc.CancelAbsence(ctx, 1)
+	require.NoError(t, err)
+	require.NotNil(t, got1.CancelledAt)
+
+	userStore.
+		On("GetAbsenceByID", ctx, int64(1)).
+		Return(got1, nil).Once()
+
+	got2, err := svc.CancelAbsence(ctx, 1)
+	require.NoError(t, err)
+	assert.Equal(t, got1.CancelledAt, got2.CancelledAt)
+}
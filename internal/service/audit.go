@@ -0,0 +1,54 @@
+package service
+
+import (
+	"context"
+	"math/rand/v2"
+
+	"avito/internal/domain"
+)
+
+// GetAssignmentAudits returns how reviewers of the pull request were chosen, oldest first.
+func (s *Service) GetAssignmentAudits(ctx context.Context, prID string) ([]domain.AssignmentAudit, error) {
+	if _, err := s.prStore.GetPullRequestByID(ctx, prID); err != nil {
+		return nil, err
+	}
+	return s.prStore.ListAssignmentAudits(ctx, prID)
+}
+
+// ReplayAssignment runs steps of the audit again with the same random source and the recorded
+// outside state (loads, history, weights, rotation), and returns chosen reviewers of every step.
+// They equal the recorded ones, steps without recorded inputs use the current state.
+// Replay is a dry run, it does not move round-robin rotation.
+func (s *Service) ReplayAssignment(ctx context.Context, audit domain.AssignmentAudit) ([][]string, error) {
+	pr, err := s.prStore.GetPullRequestByID(ctx, audit.PullRequestID)
+	if err != nil {
+		return nil, err
+	}
+
+	rng := rand.New(rand.NewPCG(audit.Seed, selectionStream(audit.PullRequestID)))
+
+	out := make([][]string, 0, len(audit.Steps))
+	for _, step := range audit.Steps {
+		candidates := make([]domain.User, 0, len(step.Candidates))
+		for _, id := range step.Candidates {
+			candidates = append(candidates, domain.User{ID: id, TeamName: step.TeamName})
+		}
+
+		chosen, err := s.selector.Select(ctx, SelectInput{
+			TeamName:   step.TeamName,
+			Strategy:   step.Strategy,
+			AuthorID:   pr.AuthorID,
+			Candidates: candidates,
+			Quantity:   step.Quantity,
+			Rand:       rng,
+			DryRun:     true,
+			Inputs:     step.Inputs,
+			Replay:     true,
+		})
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, chosen)
+	}
+	return out, nil
+}
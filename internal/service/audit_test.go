@@ -0,0 +1,163 @@
+package service
+
+import (
+	"avito/internal/domain"
+	"avito/internal/service/mocks"
+	"context"
+	"math/rand/v2"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestService_CreatePullRequest_SameSeedSameReviewers(t *testing.T) {
+	ctx := context.Background()
+
+	author := domain.User{ID: "u1", TeamName: "team-A", IsActive: true}
+	members := []domain.User{author}
+	for _, id := range []string{"u2", "u3", "u4", "u5", "u6", "u7"} {
+		members = append(members, domain.User{ID: id, TeamName: "team-A", IsActive: true})
+	}
+
+	create := func(t *testing.T) (domain.PullRequest, domain.AssignmentAudit, *Service) {
+		prStore := mocks.NewPullRequestStorage(t)
+		userStore := mocks.NewUserStorage(t)
+		teamStore := mocks.NewTeamStorage(t)
+
+		prStore.
+			On("GetPullRequestByID", ctx, "pr-1").
+			Return(domain.PullRequest{}, domain.ErrNotFound).Once()
+
+		userStore.
+			On("GetUserByID", ctx, "u1").
+			Return(&author, nil).Once()
+
+		teamStore.
+			On("GetTeamSettings", ctx, "team-A").
+			Return(defaultSettings, nil).Once()
+
+		userStore.
+			On("ListActiveUserByTeam", ctx, "team-A").
+			Return(members, nil).Once()
+
+		prStore.
+			On("Create", ctx, mock.AnythingOfType("domain.PullRequest")).
+			Return(nil).Once()
+
+		var audit domain.AssignmentAudit
+		prStore.
+			On("AddAssignmentAudits", ctx, mock.Anything).
+			Run(func(args mock.Arguments) {
+				audit = args.Get(1).([]domain.AssignmentAudit)[0]
+			}).
+			Return(nil).Once()
+
+		expectDecision(ctx, teamStore, prStore)
+
+		svc := NewService(teamStore, userStore, prStore, &mockTxManager{}, NewRandomSelector(), rand.NewPCG(42, 0))
+
+		got, err := svc.CreatePullRequest(ctx, domain.PullRequestCreate{ID: "pr-1", Name: "PR name", AuthorID: "u1"})
+		require.NoError(t, err)
+
+		prStore.
+			On("GetPullRequestByID", ctx, "pr-1").
+			Return(got, nil).Maybe()
+		return got, audit, svc
+	}
+
+	first, audit, svc := create(t)
+	second, _, _ := create(t)
+	assert.Equal(t, first.AssignedReviewers, second.AssignedReviewers)
+
+	require.Len(t, audit.Steps, 1)
+	assert.Equal(t, domain.AssignmentCreate, audit.Action)
+	assert.Equal(t, first.AssignedReviewers, audit.Steps[0].Chosen)
+
+	replayed, err := svc.ReplayAssignment(ctx, audit)
+	require.NoError(t, err)
+	assert.Equal(t, [][]string{audit.Steps[0].Chosen}, replayed)
+}
+
+func TestService_CreatePullRequest_AuditRecordsResolvedStrategy(t *testing.T) {
+	ctx := context.Background()
+
+	author := domain.User{ID: "u1", TeamName: "team-A", IsActive: true}
+
+	prStore := mocks.NewPullRequestStorage(t)
+	userStore := mocks.NewUserStorage(t)
+	teamStore := mocks.NewTeamStorage(t)
+
+	prStore.
+		On("GetPullRequestByID", ctx, "pr-1").
+		Return(domain.PullRequest{}, domain.ErrNotFound).Once()
+
+	userStore.
+		On("GetUserByID", ctx, "u1").
+		Return(&author, nil).Once()
+
+	teamStore.
+		On("GetTeamSettings", ctx, "team-A").
+		Return(defaultSettings, nil).Once()
+
+	userStore.
+		On("ListActiveUserByTeam", ctx, "team-A").
+		Return([]domain.User{
+			author,
+			{ID: "u2", TeamName: "team-A", IsActive: true},
+			{ID: "u3", TeamName: "team-A", IsActive: true},
+		}, nil).Once()
+
+	prStore.
+		On("Create", ctx, mock.AnythingOfType("domain.PullRequest")).
+		Return(nil).Once()
+
+	var audit domain.AssignmentAudit
+	prStore.
+		On("AddAssignmentAudits", ctx, mock.Anything).
+		Run(func(args mock.Arguments) {
+			audit = args.Get(1).([]domain.AssignmentAudit)[0]
+		}).
+		Return(nil).Once()
+
+	expectDecision(ctx, teamStore, prStore)
+
+	random := NewRandomSelector()
+	selector := NewStrategySelector(random, map[domain.SelectionStrategy]ReviewerSelector{
+		domain.StrategyRandom: random,
+	})
+	svc := NewService(teamStore, userStore, prStore, &mockTxManager{}, selector, nil)
+
+	_, err := svc.CreatePullRequest(ctx, domain.PullRequestCreate{ID: "pr-1", Name: "PR name", AuthorID: "u1"})
+	require.NoError(t, err)
+
+	require.Len(t, audit.Steps, 1)
+	assert.Equal(t, domain.StrategyRandom, audit.Steps[0].Strategy)
+}
+
+func TestService_ReplayAssignment_KeepsRotation(t *testing.T) {
+	ctx := context.Background()
+
+	prStore := mocks.NewPullRequestStorage(t)
+	prStore.
+		On("GetPullRequestByID", ctx, "pr-1").
+		Return(domain.PullRequest{ID: "pr-1", AuthorID: "author"}, nil).Once()
+
+	selector := NewRoundRobinSelector()
+	svc := NewService(mocks.NewTeamStorage(t), mocks.NewUserStorage(t), prStore, &mockTxManager{}, selector, nil)
+
+	replayed, err := svc.ReplayAssignment(ctx, domain.AssignmentAudit{
+		PullRequestID: "pr-1",
+		Steps: []domain.SelectionStep{{
+			TeamName:   "team-A",
+			Strategy:   domain.StrategyRoundRobin,
+			Candidates: []string{"u1", "u2"},
+			Quantity:   1,
+			Chosen:     []string{"u1"},
+		}},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, [][]string{{"u1"}}, replayed)
+	assert.Empty(t, selector.last)
+}
@@ -0,0 +1,281 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"path"
+	"slices"
+	"strings"
+	"time"
+
+	"avito/internal/domain"
+)
+
+// codeOwnersTeamPrefix marks team owners in the rules, other owners are user ids.
+const codeOwnersTeamPrefix = "team:"
+
+func (s *Service) GetCodeOwners(ctx context.Context, teamName string) ([]domain.CodeOwnersRule, error) {
+	exists, err := s.teamStore.TeamExists(ctx, teamName)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, domain.ErrNotFound
+	}
+
+	text, err := s.teamStore.GetCodeOwners(ctx, teamName)
+	if err != nil {
+		return nil, err
+	}
+	return parseCodeOwners(text)
+}
+
+// SetCodeOwners replaces code owners of the team, every owner must exist.
+func (s *Service) SetCodeOwners(ctx context.Context, teamName, text string) ([]domain.CodeOwnersRule, error) {
+	rules, err := parseCodeOwners(text)
+	if err != nil {
+		return nil, err
+	}
+
+	err = s.tx.WithTx(ctx, func(ctx context.Context) error {
+		exists, err := s.teamStore.TeamExists(ctx, teamName)
+		if err != nil {
+			return err
+		}
+		if !exists {
+			return domain.ErrNotFound
+		}
+
+		users, teams := make([]string, 0), make([]string, 0)
+		for _, rule := range rules {
+			users = appendMissing(users, rule.Users...)
+			teams = appendMissing(teams, rule.Teams...)
+		}
+
+		for _, userID := range users {
+			if _, err := s.userStore.GetUserByID(ctx, userID); err != nil {
+				if errors.Is(err, domain.ErrNotFound) {
+					return fmt.Errorf("%w: owner %q does not exist", domain.ErrInvalidInput, userID)
+				}
+				return err
+			}
+		}
+		for _, ownerTeam := range teams {
+			exists, err := s.teamStore.TeamExists(ctx, ownerTeam)
+			if err != nil {
+				return err
+			}
+			if !exists {
+				return fmt.Errorf("%w: owner team %q does not exist", domain.ErrInvalidInput, ownerTeam)
+			}
+		}
+
+		return s.teamStore.SaveCodeOwners(ctx, teamName, text)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return rules, nil
+}
+
+// pickCodeOwner chooses one available owner of the files by code owners of the team of settings.
+// Empty id is returned when files have no owners except the author or one of assigned
+// (known to the pool) is an owner, ErrNoCandidate when none of the owners can review.
+func (s *Service) pickCodeOwner(ctx context.Context, pool *candidatePool, settings domain.TeamSettings, authorID string, assigned, files []string) (string, error) {
+	if len(files) == 0 {
+		return "", nil
+	}
+
+	text, err := s.teamStore.GetCodeOwners(ctx, settings.TeamName)
+	if err != nil {
+		return "", err
+	}
+	rules, err := parseCodeOwners(text)
+	if err != nil {
+		return "", err
+	}
+
+	users, teams := codeOwnersOf(rules, files)
+	users = slices.DeleteFunc(users, func(id string) bool { return id == authorID })
+	if len(users) == 0 && len(teams) == 0 {
+		return "", nil
+	}
+	for _, id := range assigned {
+		if slices.Contains(users, id) {
+			return "", nil
+		}
+		if user, ok := pool.cachedUser(id); ok && slices.Contains(teams, user.TeamName) {
+			return "", nil
+		}
+	}
+
+	exclude := append([]string{authorID}, assigned...)
+
+	candidates := make([]domain.User, 0)
+	seen := make(map[string]bool)
+	collect := func(teamName string, accept func(domain.User) bool) error {
+		available, _, err := s.availableCandidates(ctx, pool, teamName, exclude)
+		if err != nil {
+			return err
+		}
+		for _, user := range available {
+			if !seen[user.ID] && accept(user) {
+				seen[user.ID] = true
+				candidates = append(candidates, user)
+			}
+		}
+		return nil
+	}
+
+	for _, teamName := range teams {
+		if err := collect(teamName, func(domain.User) bool { return true }); err != nil {
+			return "", err
+		}
+	}
+	for _, userID := range users {
+		user, err := s.userStore.GetUserByID(ctx, userID)
+		if errors.Is(err, domain.ErrNotFound) {
+			continue
+		}
+		if err != nil {
+			return "", err
+		}
+		if err := collect(user.TeamName, func(u domain.User) bool { return u.ID == userID }); err != nil {
+			return "", err
+		}
+	}
+
+	chosen, err := s.selectPreferred(ctx, pool, SelectInput{
+		TeamName:   settings.TeamName,
+		Strategy:   settings.Strategy,
+		AuthorID:   authorID,
+		Candidates: candidates,
+		Quantity:   1,
+		Counter:    pool,
+	}, preferenceOf(settings, time.Now()))
+	if err != nil {
+		return "", err
+	}
+	if len(chosen) == 0 {
+		return "", fmt.Errorf("%w: no available code owner", domain.ErrNoCandidate)
+	}
+	return chosen[0], nil
+}
+
+// parseCodeOwners parses CODEOWNERS-like rules: "pattern owner..." per line, "#" starts a comment.
+// Owners are user ids or team names with "team:" prefix, rule without owners makes files unowned.
+func parseCodeOwners(text string) ([]domain.CodeOwnersRule, error) {
+	rules := make([]domain.CodeOwnersRule, 0)
+	for i, line := range strings.Split(text, "\n") {
+		if idx := strings.Index(line, "#"); idx >= 0 {
+			line = line[:idx]
+		}
+		fields := strings.Fields(line)
+		if len(fields) == 0 {
+			continue
+		}
+
+		if !validCodeOwnersPattern(fields[0]) {
+			return nil, fmt.Errorf("%w: line %d: bad pattern %q", domain.ErrInvalidInput, i+1, fields[0])
+		}
+
+		rule := domain.CodeOwnersRule{Pattern: fields[0]}
+		for _, owner := range fields[1:] {
+			teamName, isTeam := strings.CutPrefix(owner, codeOwnersTeamPrefix)
+			switch {
+			case isTeam && teamName == "":
+				return nil, fmt.Errorf("%w: line %d: empty team owner", domain.ErrInvalidInput, i+1)
+			case isTeam:
+				rule.Teams = append(rule.Teams, teamName)
+			default:
+				rule.Users = append(rule.Users, owner)
+			}
+		}
+		rules = append(rules, rule)
+	}
+	return rules, nil
+}
+
+func validCodeOwnersPattern(pattern string) bool {
+	pattern = strings.Trim(pattern, "/")
+	if pattern == "" {
+		return false
+	}
+	for _, segment := range strings.Split(pattern, "/") {
+		if _, err := path.Match(segment, ""); err != nil {
+			return false
+		}
+	}
+	return true
+}
+
+// codeOwnersOf returns owners of files, the last matching rule is used for every file.
+func codeOwnersOf(rules []domain.CodeOwnersRule, files []string) ([]string, []string) {
+	users, teams := make([]string, 0), make([]string, 0)
+	for _, file := range files {
+		for i := len(rules) - 1; i >= 0; i-- {
+			if matchCodeOwnersPattern(rules[i].Pattern, file) {
+				users = appendMissing(users, rules[i].Users...)
+				teams = appendMissing(teams, rules[i].Teams...)
+				break
+			}
+		}
+	}
+	return users, teams
+}
+
+// matchCodeOwnersPattern pattern with a slash except the trailing one is relative to the root,
+// otherwise it matches at any depth. Pattern matching a directory matches every file inside,
+// pattern with the trailing slash matches directories only, "**" matches any number of directories.
+func matchCodeOwnersPattern(pattern, file string) bool {
+	dirOnly := strings.HasSuffix(pattern, "/")
+	pattern = strings.TrimSuffix(pattern, "/")
+
+	patternSegments := strings.Split(strings.TrimPrefix(pattern, "/"), "/")
+	if !strings.Contains(pattern, "/") {
+		patternSegments = append([]string{"**"}, patternSegments...)
+	}
+
+	fileSegments := strings.Split(strings.Trim(file, "/"), "/")
+	for n := len(fileSegments); n > 0; n-- {
+		if dirOnly && n == len(fileSegments) {
+			continue
+		}
+		if matchSegments(patternSegments, fileSegments[:n]) {
+			return true
+		}
+	}
+	return false
+}
+
+func matchSegments(pattern, segments []string) bool {
+	if len(pattern) == 0 {
+		return len(segments) == 0
+	}
+
+	if pattern[0] == "**" {
+		for i := 0; i <= len(segments); i++ {
+			if matchSegments(pattern[1:], segments[i:]) {
+				return true
+			}
+		}
+		return false
+	}
+
+	if len(segments) == 0 {
+		return false
+	}
+	ok, _ := path.Match(pattern[0], segments[0])
+	return ok && matchSegments(pattern[1:], segments[1:])
+}
+
+func appendMissing(dst []string, values ...string) []string {
+	for _, value := range values {
+		if !slices.Contains(dst, value) {
+			dst = append(dst, value)
+		}
+	}
+	return dst
+}
@@ -0,0 +1,235 @@
+package service
+
+import (
+	"avito/internal/domain"
+	"avito/internal/service/mocks"
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMatchCodeOwnersPattern(t *testing.T) {
+	tests := []struct {
+		pattern string
+		file    string
+		want    bool
+	}{
+		{pattern: "*.go", file: "main.go", want: true},
+		{pattern: "*.go", file: "internal/service/service.go", want: true},
+		{pattern: "*.go", file: "README.md", want: false},
+		{pattern: "/docs", file: "docs/api/openapi.yml", want: true},
+		{pattern: "/docs", file: "internal/docs/a.md", want: false},
+		{pattern: "docs/", file: "internal/docs/a.md", want: true},
+		{pattern: "docs/", file: "docs", want: false},
+		{pattern: "internal/*/mocks", file: "internal/service/mocks/team.go", want: true},
+		{pattern: "internal/**/dto.go", file: "internal/transport/http/dto.go", want: true},
+		{pattern: "internal/**/dto.go", file: "internal/dto.go", want: true},
+		{pattern: "migrations/*.sql", file: "migrations/a/b.sql", want: false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.pattern+" "+tt.file, func(t *testing.T) {
+			assert.Equal(t, tt.want, matchCodeOwnersPattern(tt.pattern, tt.file))
+		})
+	}
+}
+
+func TestParseCodeOwners(t *testing.T) {
+	rules, err := parseCodeOwners(`
+# backend
+*.go        u1 team:team-B
+/migrations u2 # dba
+/vendor
+`)
+	require.NoError(t, err)
+	assert.Equal(t, []domain.CodeOwnersRule{
+		{Pattern: "*.go", Users: []string{"u1"}, Teams: []string{"team-B"}},
+		{Pattern: "/migrations", Users: []string{"u2"}},
+		{Pattern: "/vendor"},
+	}, rules)
+
+	_, err = parseCodeOwners("[a-.go u1")
+	require.ErrorIs(t, err, domain.ErrInvalidInput)
+
+	_, err = parseCodeOwners("*.go team:")
+	require.ErrorIs(t, err, domain.ErrInvalidInput)
+}
+
+func TestCodeOwnersOf_LastRuleWins(t *testing.T) {
+	rules := []domain.CodeOwnersRule{
+		{Pattern: "*", Users: []string{"u1"}},
+		{Pattern: "/migrations", Users: []string{"u2"}, Teams: []string{"dba"}},
+		{Pattern: "/migrations/legacy"},
+	}
+
+	users, teams := codeOwnersOf(rules, []string{"migrations/0001.sql", "migrations/legacy/old.sql"})
+	assert.Equal(t, []string{"u2"}, users)
+	assert.Equal(t, []string{"dba"}, teams)
+
+	users, teams = codeOwnersOf(rules, []string{"cmd/app/main.go"})
+	assert.Equal(t, []string{"u1"}, users)
+	assert.Empty(t, teams)
+}
+
+func TestService_CreatePullRequest_AssignsCodeOwner(t *testing.T) {
+	ctx := context.Background()
+
+	prStore := mocks.NewPullRequestStorage(t)
+	userStore := mocks.NewUserStorage(t)
+	teamStore := mocks.NewTeamStorage(t)
+	tx := &mockTxManager{}
+
+	author := &domain.User{ID: "u1", TeamName: "team-A", IsActive: true}
+
+	prStore.
+		On("GetPullRequestByID", ctx, "pr-1").
+		Return(domain.PullRequest{}, domain.ErrNotFound).Once()
+
+	userStore.
+		On("GetUserByID", ctx, "u1").
+		Return(author, nil).Once()
+
+	teamStore.
+		On("GetTeamSettings", ctx, "team-A").
+		Return(defaultSettings, nil).Once()
+
+	teamStore.
+		On("GetCodeOwners", ctx, "team-A").
+		Return("/migrations team:dba\n", nil).Once()
+
+	userStore.
+		On("ListActiveUserByTeam", ctx, "dba").
+		Return([]domain.User{{ID: "d1", TeamName: "dba", IsActive: true}}, nil).Once()
+
+	userStore.
+		On("ListActiveUserByTeam", ctx, "team-A").
+		Return([]domain.User{
+			*author,
+			{ID: "u2", TeamName: "team-A", IsActive: true},
+			{ID: "u3", TeamName: "team-A", IsActive: true},
+		}, nil).Once()
+
+	prStore.
+		On("Create", ctx, mock.AnythingOfType("domain.PullRequest")).
+		Return(nil).Once()
+
+	prStore.
+		On("AddAssignmentAudits", ctx, mock.Anything).
+		Return(nil).Once()
+
+	expectDecision(ctx, teamStore, prStore)
+
+	svc := NewService(teamStore, userStore, prStore, tx, NewRandomSelector(), nil)
+
+	got, err := svc.CreatePullRequest(ctx, domain.PullRequestCreate{
+		ID:           "pr-1",
+		Name:         "PR name",
+		AuthorID:     "u1",
+		ChangedFiles: []string{"migrations/0007_code_owners.up.sql", "README.md"},
+	})
+	require.NoError(t, err)
+	require.Len(t, got.AssignedReviewers, 2)
+	assert.Equal(t, "d1", got.AssignedReviewers[0])
+	assert.Empty(t, got.FallbackReviewers)
+}
+
+func TestService_CreatePullRequest_NoAvailableCodeOwner(t *testing.T) {
+	ctx := context.Background()
+
+	prStore := mocks.NewPullRequestStorage(t)
+	userStore := mocks.NewUserStorage(t)
+	teamStore := mocks.NewTeamStorage(t)
+	tx := &mockTxManager{}
+
+	author := &domain.User{ID: "u1", TeamName: "team-A", IsActive: true}
+
+	prStore.
+		On("GetPullRequestByID", ctx, "pr-1").
+		Return(domain.PullRequest{}, domain.ErrNotFound).Once()
+
+	userStore.
+		On("GetUserByID", ctx, "u1").
+		Return(author, nil).Once()
+
+	teamStore.
+		On("GetTeamSettings", ctx, "team-A").
+		Return(defaultSettings, nil).Once()
+
+	teamStore.
+		On("GetCodeOwners", ctx, "team-A").
+		Return("*.sql u9\n", nil).Once()
+
+	userStore.
+		On("GetUserByID", ctx, "u9").
+		Return(&domain.User{ID: "u9", TeamName: "team-A", IsActive: false}, nil).Once()
+
+	userStore.
+		On("ListActiveUserByTeam", ctx, "team-A").
+		Return([]domain.User{*author, {ID: "u2", TeamName: "team-A", IsActive: true}}, nil).Once()
+
+	svc := NewService(teamStore, userStore, prStore, tx, NewRandomSelector(), nil)
+
+	_, err := svc.CreatePullRequest(ctx, domain.PullRequestCreate{
+		ID:           "pr-1",
+		Name:         "PR name",
+		AuthorID:     "u1",
+		ChangedFiles: []string{"migrations/0007_code_owners.up.sql"},
+	})
+	require.ErrorIs(t, err, domain.ErrNoCandidate)
+}
+
+func TestService_CreatePullRequest_MandatoryTakeOwnerSlot(t *testing.T) {
+	ctx := context.Background()
+
+	prStore := mocks.NewPullRequestStorage(t)
+	userStore := mocks.NewUserStorage(t)
+	teamStore := mocks.NewTeamStorage(t)
+
+	author := &domain.User{ID: "u1", TeamName: "team-A", IsActive: true}
+	settings := defaultSettings
+	settings.MandatoryReviewers = []string{"m1", "m2"}
+
+	prStore.
+		On("GetPullRequestByID", ctx, "pr-1").
+		Return(domain.PullRequest{}, domain.ErrNotFound).Once()
+
+	userStore.
+		On("GetUserByID", ctx, "u1").
+		Return(author, nil).Once()
+
+	teamStore.
+		On("GetTeamSettings", ctx, "team-A").
+		Return(settings, nil).Once()
+
+	for _, id := range []string{"m1", "m2", "u9"} {
+		userStore.
+			On("GetUserByID", ctx, id).
+			Return(&domain.User{ID: id, TeamName: "team-A", IsActive: true}, nil).Once()
+	}
+
+	userStore.
+		On("ListActiveUserByTeam", ctx, "team-A").
+		Return([]domain.User{
+			*author,
+			{ID: "m1", TeamName: "team-A", IsActive: true},
+			{ID: "m2", TeamName: "team-A", IsActive: true},
+			{ID: "u9", TeamName: "team-A", IsActive: true},
+		}, nil).Once()
+
+	teamStore.
+		On("GetCodeOwners", ctx, "team-A").
+		Return("*.sql u9\n", nil).Once()
+
+	svc := NewService(teamStore, userStore, prStore, &mockTxManager{}, NewRandomSelector(), nil)
+
+	_, err := svc.CreatePullRequest(ctx, domain.PullRequestCreate{
+		ID:           "pr-1",
+		Name:         "PR name",
+		AuthorID:     "u1",
+		ChangedFiles: []string{"migrations/0007_code_owners.up.sql"},
+	})
+	require.ErrorIs(t, err, domain.ErrNoCandidate)
+}
@@ -0,0 +1,141 @@
+package service
+
+import (
+	"context"
+	"slices"
+	"time"
+
+	"avito/internal/domain"
+)
+
+// strategyResolver is implemented by selectors which route by strategy.
+type strategyResolver interface {
+	Resolve(strategy domain.SelectionStrategy) domain.SelectionStrategy
+}
+
+// resolveStrategy returns the strategy the selector uses for the team strategy,
+// strategy itself when the selector does not route by strategy.
+func (s *Service) resolveStrategy(strategy domain.SelectionStrategy) domain.SelectionStrategy {
+	if resolver, ok := s.selector.(strategyResolver); ok {
+		return resolver.Resolve(strategy)
+	}
+	return strategy
+}
+
+// ExplainAssignment returns decisions of the pull request, oldest first.
+func (s *Service) ExplainAssignment(ctx context.Context, prID string) ([]domain.AssignmentDecision, error) {
+	if _, err := s.prStore.GetPullRequestByID(ctx, prID); err != nil {
+		return nil, err
+	}
+	return s.prStore.ListAssignmentDecisions(ctx, prID)
+}
+
+// decide builds the decision of reviewers chosen with the pool since begin. Every team
+// the pool loaded is described with all its members, assigned are reviewers who stay
+// on the PR and were not candidates, replaced is the reviewer a reassign took off the PR.
+func (s *Service) decide(ctx context.Context, pool *candidatePool, prID string, action domain.AssignmentAction, settings domain.TeamSettings, authorID string, reviewers, fallback, assigned []string, replaced string, now time.Time) (domain.AssignmentDecision, error) {
+	decision := domain.AssignmentDecision{
+		PullRequestID: prID,
+		Action:        action,
+		Strategy:      s.resolveStrategy(settings.Strategy),
+		Teams:         make([]domain.TeamCandidates, 0, len(pool.members)),
+		Reviewers:     make([]domain.ChosenReviewer, 0, len(reviewers)),
+		CreatedAt:     now,
+	}
+
+	for _, teamName := range decisionTeams(pool, settings) {
+		team, err := s.teamStore.GetWithMembers(ctx, teamName)
+		if err != nil {
+			return decision, err
+		}
+
+		candidates, err := s.teamCandidates(ctx, pool, team, authorID, reviewers, assigned, replaced)
+		if err != nil {
+			return decision, err
+		}
+		decision.Teams = append(decision.Teams, candidates)
+	}
+
+	for _, id := range reviewers {
+		chosen := domain.ChosenReviewer{
+			UserID:     id,
+			Reason:     pool.reasons[id],
+			IsFallback: slices.Contains(fallback, id),
+		}
+		if user, ok := pool.cachedUser(id); ok {
+			chosen.TeamName = user.TeamName
+		}
+		decision.Reviewers = append(decision.Reviewers, chosen)
+	}
+
+	return decision, nil
+}
+
+// decisionTeams returns teams loaded by the pool: the team of settings,
+// its backup teams in declared order, then the others by name.
+func decisionTeams(pool *candidatePool, settings domain.TeamSettings) []string {
+	out := make([]string, 0, len(pool.members))
+	for _, teamName := range append([]string{settings.TeamName}, settings.BackupTeams...) {
+		if _, ok := pool.members[teamName]; ok && !slices.Contains(out, teamName) {
+			out = append(out, teamName)
+		}
+	}
+
+	rest := make([]string, 0)
+	for teamName := range pool.members {
+		if !slices.Contains(out, teamName) {
+			rest = append(rest, teamName)
+		}
+	}
+	slices.Sort(rest)
+	return append(out, rest...)
+}
+
+// teamCandidates splits members of the team into eligible and excluded ones,
+// the first matching reason of exclusion is reported.
+func (s *Service) teamCandidates(ctx context.Context, pool *candidatePool, team *domain.Team, authorID string, reviewers, assigned []string, replaced string) (domain.TeamCandidates, error) {
+	out := domain.TeamCandidates{
+		TeamName: team.Name,
+		Eligible: make([]string, 0, len(team.Members)),
+		Excluded: make([]domain.Exclusion, 0),
+	}
+
+	active := pool.members[team.Name]
+	limited := make([]string, 0)
+	for _, user := range active {
+		if user.MaxOpenReviews != nil && !slices.Contains(reviewers, user.ID) {
+			limited = append(limited, user.ID)
+		}
+	}
+	load, err := pool.CountOpenReviews(ctx, limited)
+	if err != nil {
+		return out, err
+	}
+
+	for _, member := range team.Members {
+		var reason domain.ExclusionReason
+		idx := slices.IndexFunc(active, func(user domain.User) bool { return user.ID == member.ID })
+		switch {
+		case member.ID == authorID:
+			reason = domain.ExcludedAuthor
+		case replaced != "" && member.ID == replaced:
+			reason = domain.ExcludedReplaced
+		case slices.Contains(assigned, member.ID):
+			reason = domain.ExcludedAlreadyAssigned
+		case !member.IsActive:
+			reason = domain.ExcludedInactive
+		case idx < 0:
+			reason = domain.ExcludedAbsent
+		case slices.Contains(reviewers, member.ID):
+		case active[idx].MaxOpenReviews != nil && load[member.ID] >= *active[idx].MaxOpenReviews:
+			reason = domain.ExcludedAtCapacity
+		}
+
+		if reason == "" {
+			out.Eligible = append(out.Eligible, member.ID)
+		} else {
+			out.Excluded = append(out.Excluded, domain.Exclusion{UserID: member.ID, Reason: reason})
+		}
+	}
+	return out, nil
+}
@@ -0,0 +1,168 @@
+package service
+
+import (
+	"avito/internal/domain"
+	"avito/internal/service/mocks"
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestService_CreatePullRequest_RecordsDecision(t *testing.T) {
+	ctx := context.Background()
+
+	prStore := mocks.NewPullRequestStorage(t)
+	userStore := mocks.NewUserStorage(t)
+	teamStore := mocks.NewTeamStorage(t)
+
+	capacity := 1
+	author := domain.User{ID: "u1", TeamName: "team-A", IsActive: true}
+	free := domain.User{ID: "u2", TeamName: "team-A", IsActive: true}
+	inactive := domain.User{ID: "u3", TeamName: "team-A"}
+	absent := domain.User{ID: "u4", TeamName: "team-A", IsActive: true}
+	busy := domain.User{ID: "u5", TeamName: "team-A", IsActive: true, MaxOpenReviews: &capacity}
+
+	prStore.
+		On("GetPullRequestByID", ctx, "pr-1").
+		Return(domain.PullRequest{}, domain.ErrNotFound).Once()
+
+	userStore.
+		On("GetUserByID", ctx, "u1").
+		Return(&author, nil).Once()
+
+	teamStore.
+		On("GetTeamSettings", ctx, "team-A").
+		Return(defaultSettings, nil).Once()
+
+	userStore.
+		On("ListActiveUserByTeam", ctx, "team-A").
+		Return([]domain.User{author, free, busy}, nil).Once()
+
+	prStore.
+		On("CountOpenReviews", ctx, []string{"u5"}).
+		Return(map[string]int{"u5": 1}, nil).Once()
+
+	prStore.
+		On("Create", ctx, mock.AnythingOfType("domain.PullRequest")).
+		Return(nil).Once()
+
+	prStore.
+		On("AddAssignmentAudits", ctx, mock.Anything).
+		Return(nil).Once()
+
+	teamStore.
+		On("GetWithMembers", ctx, "team-A").
+		Return(&domain.Team{Name: "team-A", Members: []domain.User{author, free, inactive, absent, busy}}, nil).Once()
+
+	var decision domain.AssignmentDecision
+	prStore.
+		On("AddAssignmentDecision", ctx, mock.AnythingOfType("domain.AssignmentDecision")).
+		Run(func(args mock.Arguments) {
+			decision = args.Get(1).(domain.AssignmentDecision)
+		}).
+		Return(nil).Once()
+
+	svc := NewService(teamStore, userStore, prStore, &mockTxManager{}, NewRandomSelector(), nil)
+
+	got, err := svc.CreatePullRequest(ctx, domain.PullRequestCreate{ID: "pr-1", Name: "PR name", AuthorID: "u1"})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"u2"}, got.AssignedReviewers)
+
+	assert.Equal(t, "pr-1", decision.PullRequestID)
+	assert.Equal(t, domain.AssignmentCreate, decision.Action)
+	assert.Empty(t, decision.Strategy) // selector does not route by strategy
+	require.Len(t, decision.Teams, 1)
+	assert.Equal(t, "team-A", decision.Teams[0].TeamName)
+	assert.Equal(t, []string{"u2"}, decision.Teams[0].Eligible)
+	assert.Equal(t, []domain.Exclusion{
+		{UserID: "u1", Reason: domain.ExcludedAuthor},
+		{UserID: "u3", Reason: domain.ExcludedInactive},
+		{UserID: "u4", Reason: domain.ExcludedAbsent},
+		{UserID: "u5", Reason: domain.ExcludedAtCapacity},
+	}, decision.Teams[0].Excluded)
+	assert.Equal(t, []domain.ChosenReviewer{
+		{UserID: "u2", TeamName: "team-A", Reason: domain.ChosenStrategy},
+	}, decision.Reviewers)
+}
+
+func TestService_ReassignReviewer_RecordsDecision(t *testing.T) {
+	ctx := context.Background()
+
+	prStore := mocks.NewPullRequestStorage(t)
+	userStore := mocks.NewUserStorage(t)
+	teamStore := mocks.NewTeamStorage(t)
+
+	author := domain.User{ID: "u1", TeamName: "team-A", IsActive: true}
+	leaving := domain.User{ID: "r1", TeamName: "team-A", IsActive: true}
+	staying := domain.User{ID: "r2", TeamName: "team-A", IsActive: true}
+	free := domain.User{ID: "r3", TeamName: "team-A", IsActive: true}
+
+	prStore.
+		On("GetPullRequestByIDForUpdate", ctx, "pr-1").
+		Return(domain.PullRequest{ID: "pr-1", AuthorID: "u1", Status: domain.PRStatusOpen, AssignedReviewers: []string{"r1", "r2"}}, nil).Once()
+
+	userStore.
+		On("GetUserByID", ctx, "r1").
+		Return(&leaving, nil).Once()
+
+	teamStore.
+		On("GetTeamSettings", ctx, "team-A").
+		Return(defaultSettings, nil).Once()
+
+	userStore.
+		On("ListActiveUserByTeam", ctx, "team-A").
+		Return([]domain.User{author, leaving, staying, free}, nil).Once()
+
+	prStore.
+		On("ReplaceReviewer", ctx, "pr-1", "r1", "r3", false).
+		Return(nil).Once()
+
+	prStore.
+		On("AddAssignmentAudits", ctx, mock.Anything).
+		Return(nil).Once()
+
+	teamStore.
+		On("GetWithMembers", ctx, "team-A").
+		Return(&domain.Team{Name: "team-A", Members: []domain.User{author, leaving, staying, free}}, nil).Once()
+
+	var decision domain.AssignmentDecision
+	prStore.
+		On("AddAssignmentDecision", ctx, mock.AnythingOfType("domain.AssignmentDecision")).
+		Run(func(args mock.Arguments) {
+			decision = args.Get(1).(domain.AssignmentDecision)
+		}).
+		Return(nil).Once()
+
+	svc := NewService(teamStore, userStore, prStore, &mockTxManager{}, NewRandomSelector(), nil)
+
+	_, replacedBy, err := svc.ReassignReviewer(ctx, "pr-1", "r1", false)
+	require.NoError(t, err)
+	assert.Equal(t, "r3", replacedBy)
+
+	assert.Equal(t, domain.AssignmentReassign, decision.Action)
+	require.Len(t, decision.Teams, 1)
+	assert.Equal(t, []string{"r3"}, decision.Teams[0].Eligible)
+	assert.Equal(t, []domain.Exclusion{
+		{UserID: "u1", Reason: domain.ExcludedAuthor},
+		{UserID: "r1", Reason: domain.ExcludedReplaced},
+		{UserID: "r2", Reason: domain.ExcludedAlreadyAssigned},
+	}, decision.Teams[0].Excluded)
+	assert.Equal(t, []domain.ChosenReviewer{
+		{UserID: "r3", TeamName: "team-A", Reason: domain.ChosenStrategy},
+	}, decision.Reviewers)
+}
+
+func TestStrategySelector_Resolve(t *testing.T) {
+	random := NewRandomSelector()
+	selector := NewStrategySelector(random, map[domain.SelectionStrategy]ReviewerSelector{
+		domain.StrategyRandom:     random,
+		domain.StrategyRoundRobin: NewRoundRobinSelector(),
+	})
+
+	assert.Equal(t, domain.StrategyRoundRobin, selector.Resolve(domain.StrategyRoundRobin))
+	assert.Equal(t, domain.StrategyRandom, selector.Resolve(""))
+	assert.Equal(t, domain.StrategyRandom, selector.Resolve("UNKNOWN"))
+}
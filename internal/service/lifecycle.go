@@ -0,0 +1,200 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"time"
+
+	"avito/internal/domain"
+)
+
+// prTransitions allowed moves between statuses of a pull request. OPEN and NEEDS_REVIEWERS
+// follow the staffing of the PR, MERGED is final.
+var prTransitions = map[domain.PullRequestStatus][]domain.PullRequestStatus{
+	domain.PRStatusDraft:          {domain.PRStatusOpen, domain.PRStatusNeedsReviewers, domain.PRStatusClosed},
+	domain.PRStatusOpen:           {domain.PRStatusNeedsReviewers, domain.PRStatusMerged, domain.PRStatusClosed},
+	domain.PRStatusNeedsReviewers: {domain.PRStatusOpen, domain.PRStatusMerged, domain.PRStatusClosed},
+	domain.PRStatusClosed:         {domain.PRStatusDraft, domain.PRStatusOpen, domain.PRStatusNeedsReviewers},
+}
+
+// checkTransition returns ErrPRMerged for merged PR and ErrInvalidStatus for other moves
+// prTransitions does not allow.
+func checkTransition(from, to domain.PullRequestStatus) error {
+	if from == domain.PRStatusMerged {
+		return domain.ErrPRMerged
+	}
+	if !slices.Contains(prTransitions[from], to) {
+		return fmt.Errorf("%w: can not move pull request from %s to %s", domain.ErrInvalidStatus, from, to)
+	}
+	return nil
+}
+
+// requireStatus returns ErrPRMerged for merged PR and ErrInvalidStatus when PR has another status.
+func requireStatus(pr domain.PullRequest, want domain.PullRequestStatus) error {
+	if pr.Status == want {
+		return nil
+	}
+	if pr.Status == domain.PRStatusMerged {
+		return domain.ErrPRMerged
+	}
+	return fmt.Errorf("%w: pull request is %s, not %s", domain.ErrInvalidStatus, pr.Status, want)
+}
+
+// requireOpen reviewers of draft, closed and merged pull requests are not changed.
+func requireOpen(pr domain.PullRequest) error {
+	if pr.Status == domain.PRStatusMerged {
+		return domain.ErrPRMerged
+	}
+	if !pr.Status.IsOpen() {
+		return fmt.Errorf("%w: pull request is %s", domain.ErrInvalidStatus, pr.Status)
+	}
+	return nil
+}
+
+// ClosePullRequest abandons the draft or open PR, its reviewers are kept but do not count
+// as open reviews, so waiting PRs are staffed after the closing is committed.
+// Closing a closed PR changes nothing.
+func (s *Service) ClosePullRequest(ctx context.Context, prID string) (domain.PullRequest, error) {
+	var (
+		result domain.PullRequest
+		freed  bool // the closed PR was open, its reviewers have one review less now
+	)
+
+	err := s.tx.WithTx(ctx, func(ctx context.Context) error {
+		pr, err := s.prStore.GetPullRequestByIDForUpdate(ctx, prID)
+		if err != nil {
+			return err
+		}
+
+		if pr.Status == domain.PRStatusClosed { // idempotency
+			result = pr
+			return nil
+		}
+		if err := checkTransition(pr.Status, domain.PRStatusClosed); err != nil {
+			return err
+		}
+
+		now := time.Now().UTC()
+		if err := s.prStore.UpdateStatusClosed(ctx, prID, now); err != nil {
+			return err
+		}
+
+		freed = pr.Status.IsOpen()
+		pr.ClosedFrom = pr.Status
+		pr.Status = domain.PRStatusClosed
+		pr.ClosedAt = &now
+		result = pr
+		return nil
+	})
+
+	if err != nil {
+		return result, err
+	}
+
+	if freed {
+		s.staffWaitingPullRequests(ctx)
+	}
+
+	return result, nil
+}
+
+// ReopenPullRequest returns the closed PR to DRAFT when it was closed as a draft. Otherwise
+// it is OPEN with its former reviewers, and is topped up when they are less than MinReviewers.
+func (s *Service) ReopenPullRequest(ctx context.Context, prID string) (domain.PullRequest, error) {
+	var result domain.PullRequest
+
+	err := s.tx.WithTx(ctx, func(ctx context.Context) error {
+		pr, err := s.prStore.GetPullRequestByIDForUpdate(ctx, prID)
+		if err != nil {
+			return err
+		}
+		if err := requireStatus(pr, domain.PRStatusClosed); err != nil {
+			return err
+		}
+
+		status := domain.PRStatusDraft
+		if pr.ClosedFrom != domain.PRStatusDraft {
+			settings, err := s.authorSettings(ctx, pr)
+			if err != nil {
+				return err
+			}
+			status = domain.PRStatusOpen
+			if len(pr.AssignedReviewers) < settings.MinReviewers {
+				status = domain.PRStatusNeedsReviewers // topped up below
+			}
+		}
+		if err := checkTransition(pr.Status, status); err != nil {
+			return err
+		}
+
+		if err := s.prStore.UpdateStatus(ctx, prID, status); err != nil {
+			return err
+		}
+		pr.Status = status
+		pr.ClosedAt, pr.ClosedFrom = nil, ""
+		result = pr
+
+		if status != domain.PRStatusNeedsReviewers {
+			return nil
+		}
+		result, _, err = s.topUpReviewers(ctx, prID)
+		return err
+	})
+
+	return result, err
+}
+
+// MarkPullRequestReady assigns reviewers to the draft PR like CreatePullRequest does,
+// changedFiles and requiredTags are used for this choice only.
+func (s *Service) MarkPullRequestReady(ctx context.Context, prID string, changedFiles, requiredTags []string) (domain.PullRequest, error) {
+	var result domain.PullRequest
+
+	requiredTags, err := normalizeTags(requiredTags)
+	if err != nil {
+		return result, err
+	}
+
+	err = s.tx.WithTx(ctx, func(ctx context.Context) error {
+		draft, err := s.prStore.GetPullRequestByIDForUpdate(ctx, prID)
+		if err != nil {
+			return err
+		}
+		if err := requireStatus(draft, domain.PRStatusDraft); err != nil {
+			return err
+		}
+
+		pool := s.newCandidatePool()
+		pr, decision, err := s.planPullRequest(ctx, pool, domain.PullRequestCreate{
+			ID:           draft.ID,
+			Name:         draft.Name,
+			AuthorID:     draft.AuthorID,
+			ChangedFiles: changedFiles,
+			RequiredTags: requiredTags,
+		}, time.Now().UTC())
+		if err != nil {
+			return err
+		}
+		pr.CreatedAt = draft.CreatedAt
+		if err := checkTransition(draft.Status, pr.Status); err != nil {
+			return err
+		}
+
+		if err := s.prStore.MarkReady(ctx, pr); err != nil {
+			return err
+		}
+
+		audit := pool.audit(pr.ID, domain.AssignmentCreate, decision.CreatedAt)
+		if err := s.prStore.AddAssignmentAudits(ctx, []domain.AssignmentAudit{audit}); err != nil {
+			return err
+		}
+		if err := s.prStore.AddAssignmentDecision(ctx, decision); err != nil {
+			return err
+		}
+
+		result = pr
+		return nil
+	})
+
+	return result, err
+}
@@ -0,0 +1,251 @@
+package service
+
+import (
+	"avito/internal/domain"
+	"avito/internal/service/mocks"
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_checkTransition(t *testing.T) {
+	tests := []struct {
+		from, to domain.PullRequestStatus
+		wantErr  error
+	}{
+		{from: domain.PRStatusDraft, to: domain.PRStatusOpen},
+		{from: domain.PRStatusDraft, to: domain.PRStatusClosed},
+		{from: domain.PRStatusDraft, to: domain.PRStatusMerged, wantErr: domain.ErrInvalidStatus},
+		{from: domain.PRStatusOpen, to: domain.PRStatusMerged},
+		{from: domain.PRStatusNeedsReviewers, to: domain.PRStatusClosed},
+		{from: domain.PRStatusOpen, to: domain.PRStatusDraft, wantErr: domain.ErrInvalidStatus},
+		{from: domain.PRStatusClosed, to: domain.PRStatusDraft},
+		{from: domain.PRStatusClosed, to: domain.PRStatusMerged, wantErr: domain.ErrInvalidStatus},
+		{from: domain.PRStatusMerged, to: domain.PRStatusClosed, wantErr: domain.ErrPRMerged},
+	}
+
+	for _, tt := range tests {
+		t.Run(string(tt.from)+"_to_"+string(tt.to), func(t *testing.T) {
+			err := checkTransition(tt.from, tt.to)
+			if tt.wantErr != nil {
+				require.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+		})
+	}
+}
+
+func TestService_CreatePullRequest_DraftDefersAssignment(t *testing.T) {
+	ctx := context.Background()
+
+	userStore := mocks.NewUserStorage(t)
+	prStore := mocks.NewPullRequestStorage(t)
+
+	prStore.
+		On("GetPullRequestByID", ctx, "pr-1").
+		Return(domain.PullRequest{}, domain.ErrNotFound).Once()
+
+	userStore.
+		On("GetUserByID", ctx, "u1").
+		Return(&domain.User{ID: "u1", TeamName: "team-A"}, nil).Once()
+
+	prStore.
+		On("Create", ctx, mock.MatchedBy(func(pr domain.PullRequest) bool {
+			return pr.Status == domain.PRStatusDraft && len(pr.AssignedReviewers) == 0
+		})).
+		Return(nil).Once()
+
+	svc := NewService(mocks.NewTeamStorage(t), userStore, prStore, &mockTxManager{}, NewRandomSelector(), nil)
+
+	got, err := svc.CreatePullRequest(ctx, domain.PullRequestCreate{ID: "pr-1", AuthorID: "u1", Draft: true})
+	require.NoError(t, err)
+	assert.Equal(t, domain.PRStatusDraft, got.Status)
+	assert.Empty(t, got.AssignedReviewers)
+}
+
+func TestService_MarkPullRequestReady(t *testing.T) {
+	ctx := context.Background()
+
+	teamStore := mocks.NewTeamStorage(t)
+	userStore := mocks.NewUserStorage(t)
+	prStore := mocks.NewPullRequestStorage(t)
+
+	created := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	author := domain.User{ID: "u1", TeamName: "team-A", IsActive: true}
+	members := []domain.User{
+		author,
+		{ID: "u2", TeamName: "team-A", IsActive: true},
+		{ID: "u3", TeamName: "team-A", IsActive: true},
+	}
+
+	prStore.
+		On("GetPullRequestByIDForUpdate", ctx, "pr-1").
+		Return(domain.PullRequest{ID: "pr-1", AuthorID: "u1", Status: domain.PRStatusDraft, CreatedAt: &created}, nil).Once()
+
+	userStore.
+		On("GetUserByID", ctx, "u1").
+		Return(&author, nil).Once()
+
+	teamStore.
+		On("GetTeamSettings", ctx, "team-A").
+		Return(defaultSettings, nil).Once()
+
+	userStore.
+		On("ListActiveUserByTeam", ctx, "team-A").
+		Return(members, nil).Once()
+
+	prStore.
+		On("MarkReady", ctx, mock.MatchedBy(func(pr domain.PullRequest) bool {
+			return pr.Status == domain.PRStatusOpen && len(pr.AssignedReviewers) == 2
+		})).
+		Return(nil).Once()
+
+	prStore.
+		On("AddAssignmentAudits", ctx, mock.Anything).
+		Return(nil).Once()
+
+	expectDecision(ctx, teamStore, prStore, members...)
+
+	svc := NewService(teamStore, userStore, prStore, &mockTxManager{}, NewRandomSelector(), nil)
+
+	got, err := svc.MarkPullRequestReady(ctx, "pr-1", nil, nil)
+	require.NoError(t, err)
+	assert.Equal(t, domain.PRStatusOpen, got.Status)
+	assert.ElementsMatch(t, []string{"u2", "u3"}, got.AssignedReviewers)
+	assert.Equal(t, &created, got.CreatedAt)
+}
+
+func TestService_ClosePullRequest(t *testing.T) {
+	ctx := context.Background()
+
+	prStore := mocks.NewPullRequestStorage(t)
+
+	prStore.
+		On("GetPullRequestByIDForUpdate", ctx, "pr-1").
+		Return(domain.PullRequest{ID: "pr-1", Status: domain.PRStatusOpen, AssignedReviewers: []string{"u2"}}, nil).Once()
+
+	prStore.
+		On("UpdateStatusClosed", ctx, "pr-1", mock.AnythingOfType("time.Time")).
+		Return(nil).Once()
+
+	prStore.
+		On("ListByStatus", ctx, domain.PRStatusNeedsReviewers, staffBatchSize).
+		Return([]domain.PullRequest{}, nil).Once()
+
+	svc := NewService(mocks.NewTeamStorage(t), mocks.NewUserStorage(t), prStore, &mockTxManager{}, NewRandomSelector(), nil)
+
+	got, err := svc.ClosePullRequest(ctx, "pr-1")
+	require.NoError(t, err)
+	assert.Equal(t, domain.PRStatusClosed, got.Status)
+	assert.Equal(t, domain.PRStatusOpen, got.ClosedFrom)
+	assert.NotNil(t, got.ClosedAt)
+
+	prStore.
+		On("GetPullRequestByIDForUpdate", ctx, "pr-1").
+		Return(got, nil).Once()
+
+	again, err := svc.ClosePullRequest(ctx, "pr-1")
+	require.NoError(t, err)
+	assert.Equal(t, got.ClosedAt, again.ClosedAt)
+}
+
+func TestService_ClosePullRequest_StaffingFailureDoesNotFailClose(t *testing.T) {
+	ctx := context.Background()
+
+	prStore := mocks.NewPullRequestStorage(t)
+
+	prStore.
+		On("GetPullRequestByIDForUpdate", ctx, "pr-1").
+		Return(domain.PullRequest{ID: "pr-1", Status: domain.PRStatusNeedsReviewers}, nil).Once()
+
+	prStore.
+		On("UpdateStatusClosed", ctx, "pr-1", mock.AnythingOfType("time.Time")).
+		Return(nil).Once()
+
+	prStore.
+		On("ListByStatus", ctx, domain.PRStatusNeedsReviewers, staffBatchSize).
+		Return(nil, errors.New("db is down")).Once()
+
+	svc := NewService(mocks.NewTeamStorage(t), mocks.NewUserStorage(t), prStore, &mockTxManager{}, NewRandomSelector(), nil)
+
+	got, err := svc.ClosePullRequest(ctx, "pr-1")
+	require.NoError(t, err)
+	assert.Equal(t, domain.PRStatusClosed, got.Status)
+}
+
+func TestService_ReopenPullRequest(t *testing.T) {
+	ctx := context.Background()
+	closedAt := time.Now().UTC()
+
+	tests := []struct {
+		name       string
+		pr         domain.PullRequest
+		wantStatus domain.PullRequestStatus
+		wantErr    error
+	}{
+		{
+			name:       "closed_draft",
+			pr:         domain.PullRequest{ID: "pr-1", AuthorID: "u1", Status: domain.PRStatusClosed, ClosedFrom: domain.PRStatusDraft, ClosedAt: &closedAt},
+			wantStatus: domain.PRStatusDraft,
+		},
+		{
+			name:       "staffed",
+			pr:         domain.PullRequest{ID: "pr-1", AuthorID: "u1", Status: domain.PRStatusClosed, ClosedFrom: domain.PRStatusOpen, ClosedAt: &closedAt, AssignedReviewers: []string{"u2", "u3"}},
+			wantStatus: domain.PRStatusOpen,
+		},
+		{
+			name:    "open",
+			pr:      domain.PullRequest{ID: "pr-1", AuthorID: "u1", Status: domain.PRStatusOpen},
+			wantErr: domain.ErrInvalidStatus,
+		},
+		{
+			name:    "merged",
+			pr:      domain.PullRequest{ID: "pr-1", AuthorID: "u1", Status: domain.PRStatusMerged},
+			wantErr: domain.ErrPRMerged,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			teamStore := mocks.NewTeamStorage(t)
+			userStore := mocks.NewUserStorage(t)
+			prStore := mocks.NewPullRequestStorage(t)
+
+			prStore.
+				On("GetPullRequestByIDForUpdate", ctx, "pr-1").
+				Return(tt.pr, nil).Once()
+
+			if tt.wantStatus == domain.PRStatusOpen {
+				userStore.
+					On("GetUserByID", ctx, "u1").
+					Return(&domain.User{ID: "u1", TeamName: "team-A"}, nil).Once()
+
+				teamStore.
+					On("GetTeamSettings", ctx, "team-A").
+					Return(defaultSettings, nil).Once()
+			}
+			if tt.wantErr == nil {
+				prStore.
+					On("UpdateStatus", ctx, "pr-1", tt.wantStatus).
+					Return(nil).Once()
+			}
+
+			svc := NewService(teamStore, userStore, prStore, &mockTxManager{}, NewRandomSelector(), nil)
+
+			got, err := svc.ReopenPullRequest(ctx, "pr-1")
+			if tt.wantErr != nil {
+				require.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantStatus, got.Status)
+			assert.Nil(t, got.ClosedAt)
+		})
+	}
+}
@@ -0,0 +1,79 @@
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"avito/internal/domain"
+)
+
+const (
+	defaultPageSize = 50
+	maxPageSize     = 200
+)
+
+// GetPullRequest returns the pull request by id in any status.
+func (s *Service) GetPullRequest(ctx context.Context, prID string) (domain.PullRequest, error) {
+	return s.prStore.GetPullRequestByID(ctx, prID)
+}
+
+// ListPullRequests returns one page of pull requests matching filter, zero Limit means
+// defaultPageSize.
+func (s *Service) ListPullRequests(ctx context.Context, filter domain.PullRequestFilter) (domain.PullRequestPage, error) {
+	if err := normalizePullRequestFilter(&filter); err != nil {
+		return domain.PullRequestPage{}, err
+	}
+	return s.listPage(ctx, filter)
+}
+
+// listPage asks one pull request more than filter.Limit to know whether there is the next page.
+func (s *Service) listPage(ctx context.Context, filter domain.PullRequestFilter) (domain.PullRequestPage, error) {
+	limit := filter.Limit
+	filter.Limit++
+
+	prs, err := s.prStore.ListPullRequests(ctx, filter)
+	if err != nil {
+		return domain.PullRequestPage{}, err
+	}
+
+	page := domain.PullRequestPage{PullRequests: prs}
+	if len(prs) > limit {
+		page.PullRequests = prs[:limit]
+		last := page.PullRequests[limit-1]
+		page.Next = &domain.PullRequestCursor{CreatedAt: *last.CreatedAt, ID: last.ID}
+	}
+	return page, nil
+}
+
+func normalizePullRequestFilter(filter *domain.PullRequestFilter) error {
+	for _, status := range filter.Statuses {
+		if !status.IsValid() {
+			return fmt.Errorf("%w: unknown status %q", domain.ErrInvalidInput, status)
+		}
+	}
+
+	labels, err := normalizeLabels(filter.Labels)
+	if err != nil {
+		return err
+	}
+	filter.Labels = labels
+
+	if filter.CreatedFrom != nil && filter.CreatedTo != nil && !filter.CreatedFrom.Before(*filter.CreatedTo) {
+		return fmt.Errorf("%w: created_from must be before created_to", domain.ErrInvalidInput)
+	}
+	if filter.MergedFrom != nil && filter.MergedTo != nil && !filter.MergedFrom.Before(*filter.MergedTo) {
+		return fmt.Errorf("%w: merged_from must be before merged_to", domain.ErrInvalidInput)
+	}
+
+	return normalizeLimit(&filter.Limit)
+}
+
+func normalizeLimit(limit *int) error {
+	switch {
+	case *limit == 0:
+		*limit = defaultPageSize
+	case *limit < 0 || *limit > maxPageSize:
+		return fmt.Errorf("%w: limit must be between 1 and %d", domain.ErrInvalidInput, maxPageSize)
+	}
+	return nil
+}
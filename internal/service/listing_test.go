@@ -0,0 +1,112 @@
+package service
+
+import (
+	"avito/internal/domain"
+	"avito/internal/service/mocks"
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestService_ListPullRequests_Pages(t *testing.T) {
+	ctx := context.Background()
+
+	base := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	prs := make([]domain.PullRequest, 0, 3)
+	for i, id := range []string{"pr-1", "pr-2", "pr-3"} {
+		createdAt := base.Add(time.Duration(i) * time.Hour)
+		prs = append(prs, domain.PullRequest{ID: id, CreatedAt: &createdAt})
+	}
+
+	prStore := mocks.NewPullRequestStorage(t)
+
+	prStore.
+		On("ListPullRequests", ctx, mock.MatchedBy(func(filter domain.PullRequestFilter) bool {
+			return filter.Limit == 3 && filter.After == nil && filter.Labels[0] == "bug"
+		})).
+		Return(prs, nil).Once()
+
+	prStore.
+		On("ListPullRequests", ctx, mock.MatchedBy(func(filter domain.PullRequestFilter) bool {
+			return filter.Limit == 3 && filter.After != nil && filter.After.ID == "pr-2"
+		})).
+		Return(prs[2:], nil).Once()
+
+	svc := NewService(mocks.NewTeamStorage(t), mocks.NewUserStorage(t), prStore, &mockTxManager{}, NewRandomSelector(), nil)
+
+	first, err := svc.ListPullRequests(ctx, domain.PullRequestFilter{Labels: []string{"Bug"}, Limit: 2})
+	require.NoError(t, err)
+	require.Len(t, first.PullRequests, 2)
+	require.NotNil(t, first.Next)
+	assert.Equal(t, domain.PullRequestCursor{CreatedAt: *prs[1].CreatedAt, ID: "pr-2"}, *first.Next)
+
+	second, err := svc.ListPullRequests(ctx, domain.PullRequestFilter{Limit: 2, After: first.Next})
+	require.NoError(t, err)
+	assert.Equal(t, prs[2:], second.PullRequests)
+	assert.Nil(t, second.Next)
+}
+
+func TestService_ListPullRequests_InvalidFilter(t *testing.T) {
+	ctx := context.Background()
+	now := time.Now().UTC()
+
+	tests := []struct {
+		name   string
+		filter domain.PullRequestFilter
+	}{
+		{name: "unknown_status", filter: domain.PullRequestFilter{Statuses: []domain.PullRequestStatus{"REJECTED"}}},
+		{name: "empty_created_range", filter: domain.PullRequestFilter{CreatedFrom: &now, CreatedTo: &now}},
+		{name: "negative_limit", filter: domain.PullRequestFilter{Limit: -1}},
+		{name: "too_big_limit", filter: domain.PullRequestFilter{Limit: maxPageSize + 1}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc := NewService(mocks.NewTeamStorage(t), mocks.NewUserStorage(t), mocks.NewPullRequestStorage(t), &mockTxManager{}, NewRandomSelector(), nil)
+
+			_, err := svc.ListPullRequests(ctx, tt.filter)
+			require.ErrorIs(t, err, domain.ErrInvalidInput)
+		})
+	}
+}
+
+func TestService_GetUserReviews(t *testing.T) {
+	ctx := context.Background()
+
+	createdAt := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	prs := []domain.PullRequest{
+		{ID: "pr-1", CreatedAt: &createdAt},
+		{ID: "pr-2", CreatedAt: &createdAt},
+	}
+
+	userStore := mocks.NewUserStorage(t)
+	prStore := mocks.NewPullRequestStorage(t)
+
+	userStore.
+		On("GetUserByID", ctx, "u1").
+		Return(&domain.User{ID: "u1", TeamName: "team-A"}, nil).Once()
+
+	prStore.
+		On("ListPullRequests", ctx, domain.PullRequestFilter{
+			Statuses:   []domain.PullRequestStatus{domain.PRStatusOpen},
+			ReviewerID: "u1",
+			Labels:     []string{},
+			Limit:      2,
+		}).
+		Return(prs, nil).Once()
+
+	svc := NewService(mocks.NewTeamStorage(t), userStore, prStore, &mockTxManager{}, NewRandomSelector(), nil)
+
+	got, err := svc.GetUserReviews(ctx, "u1", domain.PullRequestFilter{
+		Statuses: []domain.PullRequestStatus{domain.PRStatusOpen},
+		AuthorID: "ignored",
+		Limit:    1,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, prs[:1], got.PullRequests)
+	assert.Equal(t, &domain.PullRequestCursor{CreatedAt: createdAt, ID: "pr-1"}, got.Next)
+}
@@ -0,0 +1,107 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"avito/internal/domain"
+)
+
+const (
+	maxLabelLength       = 50
+	maxLabels            = 20
+	maxDescriptionLength = 10000
+)
+
+// UpdatePullRequest changes name, description, url and labels of the not finished PR,
+// nil fields of update are left unchanged.
+func (s *Service) UpdatePullRequest(ctx context.Context, prID string, update domain.PullRequestUpdate) (domain.PullRequest, error) {
+	var result domain.PullRequest
+
+	if err := normalizePullRequestUpdate(&update); err != nil {
+		return result, err
+	}
+
+	err := s.tx.WithTx(ctx, func(ctx context.Context) error {
+		pr, err := s.prStore.GetPullRequestByIDForUpdate(ctx, prID)
+		if err != nil {
+			return err
+		}
+		if pr.Status == domain.PRStatusMerged {
+			return domain.ErrPRMerged
+		}
+		if pr.Status == domain.PRStatusClosed {
+			return fmt.Errorf("%w: pull request is %s", domain.ErrInvalidStatus, pr.Status)
+		}
+
+		if update.Name != nil {
+			pr.Name = *update.Name
+		}
+		if update.Description != nil {
+			pr.Description = *update.Description
+		}
+		if update.URL != nil {
+			pr.URL = *update.URL
+		}
+		if err := s.prStore.UpdateMetadata(ctx, pr); err != nil {
+			return err
+		}
+
+		if update.Labels != nil {
+			if err := s.prStore.SetLabels(ctx, prID, *update.Labels); err != nil {
+				return err
+			}
+			pr.Labels = *update.Labels
+		}
+
+		result = pr
+		return nil
+	})
+
+	return result, err
+}
+
+func normalizePullRequestUpdate(update *domain.PullRequestUpdate) error {
+	if update.Name != nil {
+		name := strings.TrimSpace(*update.Name)
+		if name == "" {
+			return fmt.Errorf("%w: pull_request_name must not be empty", domain.ErrInvalidInput)
+		}
+		update.Name = &name
+	}
+	if update.Description != nil && len(*update.Description) > maxDescriptionLength {
+		return fmt.Errorf("%w: description must be at most %d bytes", domain.ErrInvalidInput, maxDescriptionLength)
+	}
+	if update.URL != nil && *update.URL != "" {
+		u, err := url.Parse(*update.URL)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return fmt.Errorf("%w: url must be an absolute http(s) url", domain.ErrInvalidInput)
+		}
+	}
+	if update.Labels != nil {
+		labels, err := normalizeLabels(*update.Labels)
+		if err != nil {
+			return err
+		}
+		update.Labels = &labels
+	}
+	return nil
+}
+
+// normalizeLabels lower cases labels and drops duplicates keeping the order.
+func normalizeLabels(labels []string) ([]string, error) {
+	out := make([]string, 0, len(labels))
+	for _, label := range labels {
+		label = strings.ToLower(strings.TrimSpace(label))
+		if label == "" || len(label) > maxLabelLength || strings.ContainsAny(label, "\t\n,") {
+			return nil, fmt.Errorf("%w: bad label %q", domain.ErrInvalidInput, label)
+		}
+		out = appendMissing(out, label)
+	}
+	if len(out) > maxLabels {
+		return nil, fmt.Errorf("%w: at most %d labels are allowed", domain.ErrInvalidInput, maxLabels)
+	}
+	return out, nil
+}
@@ -0,0 +1,100 @@
+package service
+
+import (
+	"avito/internal/domain"
+	"avito/internal/service/mocks"
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func ptr[T any](v T) *T {
+	return &v
+}
+
+func TestService_UpdatePullRequest(t *testing.T) {
+	ctx := context.Background()
+
+	open := domain.PullRequest{ID: "pr-1", Name: "old", Status: domain.PRStatusOpen, Labels: []string{"backend"}}
+
+	tests := []struct {
+		name       string
+		pr         *domain.PullRequest
+		update     domain.PullRequestUpdate
+		wantErr    error
+		wantLabels []string
+	}{
+		{
+			name:       "rename_and_relabel",
+			pr:         &open,
+			update:     domain.PullRequestUpdate{Name: ptr(" new "), URL: ptr("https://git.example.com/pr/1"), Labels: &[]string{"Bug", "bug", "urgent"}},
+			wantLabels: []string{"bug", "urgent"},
+		},
+		{
+			name:       "labels_unchanged",
+			pr:         &open,
+			update:     domain.PullRequestUpdate{Description: ptr("text")},
+			wantLabels: []string{"backend"},
+		},
+		{
+			name:    "empty_name",
+			update:  domain.PullRequestUpdate{Name: ptr("  ")},
+			wantErr: domain.ErrInvalidInput,
+		},
+		{
+			name:    "bad_url",
+			update:  domain.PullRequestUpdate{URL: ptr("ftp://example.com")},
+			wantErr: domain.ErrInvalidInput,
+		},
+		{
+			name:    "merged",
+			pr:      &domain.PullRequest{ID: "pr-1", Status: domain.PRStatusMerged},
+			update:  domain.PullRequestUpdate{Description: ptr("text")},
+			wantErr: domain.ErrPRMerged,
+		},
+		{
+			name:    "closed",
+			pr:      &domain.PullRequest{ID: "pr-1", Status: domain.PRStatusClosed},
+			update:  domain.PullRequestUpdate{Description: ptr("text")},
+			wantErr: domain.ErrInvalidStatus,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			prStore := mocks.NewPullRequestStorage(t)
+
+			if tt.pr != nil {
+				prStore.
+					On("GetPullRequestByIDForUpdate", ctx, "pr-1").
+					Return(*tt.pr, nil).Once()
+			}
+			if tt.wantErr == nil {
+				prStore.
+					On("UpdateMetadata", ctx, mock.AnythingOfType("domain.PullRequest")).
+					Return(nil).Once()
+			}
+			if tt.wantErr == nil && tt.update.Labels != nil {
+				prStore.
+					On("SetLabels", ctx, "pr-1", tt.wantLabels).
+					Return(nil).Once()
+			}
+
+			svc := NewService(mocks.NewTeamStorage(t), mocks.NewUserStorage(t), prStore, &mockTxManager{}, NewRandomSelector(), nil)
+
+			got, err := svc.UpdatePullRequest(ctx, "pr-1", tt.update)
+			if tt.wantErr != nil {
+				require.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantLabels, got.Labels)
+			if tt.update.Name != nil {
+				assert.Equal(t, "new", got.Name)
+			}
+		})
+	}
+}
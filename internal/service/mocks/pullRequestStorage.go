@@ -16,6 +16,90 @@ type PullRequestStorage struct {
 	mock.Mock
 }
 
+// AddAssignmentAudits provides a mock function with given fields: ctx, audits
+func (_m *PullRequestStorage) AddAssignmentAudits(ctx context.Context, audits []domain.AssignmentAudit) error {
+	ret := _m.Called(ctx, audits)
+
+	if len(ret) == 0 {
+		panic("no return value specified for AddAssignmentAudits")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, []domain.AssignmentAudit) error); ok {
+		r0 = rf(ctx, audits)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// AddAssignmentDecision provides a mock function with given fields: ctx, decision
+func (_m *PullRequestStorage) AddAssignmentDecision(ctx context.Context, decision domain.AssignmentDecision) error {
+	ret := _m.Called(ctx, decision)
+
+	if len(ret) == 0 {
+		panic("no return value specified for AddAssignmentDecision")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, domain.AssignmentDecision) error); ok {
+		r0 = rf(ctx, decision)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// AddReviewer provides a mock function with given fields: ctx, pullRequestID, userID, isFallback, isMandatory
+func (_m *PullRequestStorage) AddReviewer(ctx context.Context, pullRequestID string, userID string, isFallback bool, isMandatory bool) error {
+	ret := _m.Called(ctx, pullRequestID, userID, isFallback, isMandatory)
+
+	if len(ret) == 0 {
+		panic("no return value specified for AddReviewer")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, bool, bool) error); ok {
+		r0 = rf(ctx, pullRequestID, userID, isFallback, isMandatory)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// CountOpenReviews provides a mock function with given fields: ctx, userIDs
+func (_m *PullRequestStorage) CountOpenReviews(ctx context.Context, userIDs []string) (map[string]int, error) {
+	ret := _m.Called(ctx, userIDs)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CountOpenReviews")
+	}
+
+	var r0 map[string]int
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, []string) (map[string]int, error)); ok {
+		return rf(ctx, userIDs)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, []string) map[string]int); ok {
+		r0 = rf(ctx, userIDs)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(map[string]int)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, []string) error); ok {
+		r1 = rf(ctx, userIDs)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // Create provides a mock function with given fields: ctx, pullRequest
 func (_m *PullRequestStorage) Create(ctx context.Context, pullRequest domain.PullRequest) error {
 	ret := _m.Called(ctx, pullRequest)
@@ -90,29 +174,119 @@ func (_m *PullRequestStorage) GetPullRequestByIDForUpdate(ctx context.Context, p
 	return r0, r1
 }
 
-// ListByReviewer provides a mock function with given fields: ctx, userID
-func (_m *PullRequestStorage) ListByReviewer(ctx context.Context, userID string) ([]domain.PullRequest, error) {
-	ret := _m.Called(ctx, userID)
+// ListAssignmentAudits provides a mock function with given fields: ctx, pullRequestID
+func (_m *PullRequestStorage) ListAssignmentAudits(ctx context.Context, pullRequestID string) ([]domain.AssignmentAudit, error) {
+	ret := _m.Called(ctx, pullRequestID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListAssignmentAudits")
+	}
+
+	var r0 []domain.AssignmentAudit
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) ([]domain.AssignmentAudit, error)); ok {
+		return rf(ctx, pullRequestID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) []domain.AssignmentAudit); ok {
+		r0 = rf(ctx, pullRequestID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]domain.AssignmentAudit)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, pullRequestID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ListAssignmentDecisions provides a mock function with given fields: ctx, pullRequestID
+func (_m *PullRequestStorage) ListAssignmentDecisions(ctx context.Context, pullRequestID string) ([]domain.AssignmentDecision, error) {
+	ret := _m.Called(ctx, pullRequestID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListAssignmentDecisions")
+	}
+
+	var r0 []domain.AssignmentDecision
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) ([]domain.AssignmentDecision, error)); ok {
+		return rf(ctx, pullRequestID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) []domain.AssignmentDecision); ok {
+		r0 = rf(ctx, pullRequestID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]domain.AssignmentDecision)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, pullRequestID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ListByStatus provides a mock function with given fields: ctx, status, limit
+func (_m *PullRequestStorage) ListByStatus(ctx context.Context, status domain.PullRequestStatus, limit int) ([]domain.PullRequest, error) {
+	ret := _m.Called(ctx, status, limit)
 
 	if len(ret) == 0 {
-		panic("no return value specified for ListByReviewer")
+		panic("no return value specified for ListByStatus")
 	}
 
 	var r0 []domain.PullRequest
 	var r1 error
-	if rf, ok := ret.Get(0).(func(context.Context, string) ([]domain.PullRequest, error)); ok {
-		return rf(ctx, userID)
+	if rf, ok := ret.Get(0).(func(context.Context, domain.PullRequestStatus, int) ([]domain.PullRequest, error)); ok {
+		return rf(ctx, status, limit)
 	}
-	if rf, ok := ret.Get(0).(func(context.Context, string) []domain.PullRequest); ok {
-		r0 = rf(ctx, userID)
+	if rf, ok := ret.Get(0).(func(context.Context, domain.PullRequestStatus, int) []domain.PullRequest); ok {
+		r0 = rf(ctx, status, limit)
 	} else {
 		if ret.Get(0) != nil {
 			r0 = ret.Get(0).([]domain.PullRequest)
 		}
 	}
 
-	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
-		r1 = rf(ctx, userID)
+	if rf, ok := ret.Get(1).(func(context.Context, domain.PullRequestStatus, int) error); ok {
+		r1 = rf(ctx, status, limit)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ListOpenByReviewersForUpdate provides a mock function with given fields: ctx, userIDs
+func (_m *PullRequestStorage) ListOpenByReviewersForUpdate(ctx context.Context, userIDs []string) ([]domain.PullRequest, error) {
+	ret := _m.Called(ctx, userIDs)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListOpenByReviewersForUpdate")
+	}
+
+	var r0 []domain.PullRequest
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, []string) ([]domain.PullRequest, error)); ok {
+		return rf(ctx, userIDs)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, []string) []domain.PullRequest); ok {
+		r0 = rf(ctx, userIDs)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]domain.PullRequest)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, []string) error); ok {
+		r1 = rf(ctx, userIDs)
 	} else {
 		r1 = ret.Error(1)
 	}
@@ -120,17 +294,299 @@ func (_m *PullRequestStorage) ListByReviewer(ctx context.Context, userID string)
 	return r0, r1
 }
 
-// ReplaceReviewer provides a mock function with given fields: ctx, pullRequestID, oldID, newID
-func (_m *PullRequestStorage) ReplaceReviewer(ctx context.Context, pullRequestID string, oldID string, newID string) error {
-	ret := _m.Called(ctx, pullRequestID, oldID, newID)
+// ListPullRequests provides a mock function with given fields: ctx, filter
+func (_m *PullRequestStorage) ListPullRequests(ctx context.Context, filter domain.PullRequestFilter) ([]domain.PullRequest, error) {
+	ret := _m.Called(ctx, filter)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListPullRequests")
+	}
+
+	var r0 []domain.PullRequest
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, domain.PullRequestFilter) ([]domain.PullRequest, error)); ok {
+		return rf(ctx, filter)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, domain.PullRequestFilter) []domain.PullRequest); ok {
+		r0 = rf(ctx, filter)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]domain.PullRequest)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, domain.PullRequestFilter) error); ok {
+		r1 = rf(ctx, filter)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ListUnderstaffed provides a mock function with given fields: ctx, limit
+func (_m *PullRequestStorage) ListUnderstaffed(ctx context.Context, limit int) ([]domain.PullRequest, error) {
+	ret := _m.Called(ctx, limit)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListUnderstaffed")
+	}
+
+	var r0 []domain.PullRequest
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int) ([]domain.PullRequest, error)); ok {
+		return rf(ctx, limit)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int) []domain.PullRequest); ok {
+		r0 = rf(ctx, limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]domain.PullRequest)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int) error); ok {
+		r1 = rf(ctx, limit)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// MarkBackfillAttempted provides a mock function with given fields: ctx, pullRequestIDs
+func (_m *PullRequestStorage) MarkBackfillAttempted(ctx context.Context, pullRequestIDs []string) error {
+	ret := _m.Called(ctx, pullRequestIDs)
+
+	if len(ret) == 0 {
+		panic("no return value specified for MarkBackfillAttempted")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, []string) error); ok {
+		r0 = rf(ctx, pullRequestIDs)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// MarkReady provides a mock function with given fields: ctx, pullRequest
+func (_m *PullRequestStorage) MarkReady(ctx context.Context, pullRequest domain.PullRequest) error {
+	ret := _m.Called(ctx, pullRequest)
+
+	if len(ret) == 0 {
+		panic("no return value specified for MarkReady")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, domain.PullRequest) error); ok {
+		r0 = rf(ctx, pullRequest)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// RecentReviewers provides a mock function with given fields: ctx, authorID, limit
+func (_m *PullRequestStorage) RecentReviewers(ctx context.Context, authorID string, limit int) ([]string, error) {
+	ret := _m.Called(ctx, authorID, limit)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RecentReviewers")
+	}
+
+	var r0 []string
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, int) ([]string, error)); ok {
+		return rf(ctx, authorID, limit)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string, int) []string); ok {
+		r0 = rf(ctx, authorID, limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]string)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string, int) error); ok {
+		r1 = rf(ctx, authorID, limit)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// RecentReviewersByAuthors provides a mock function with given fields: ctx, authorIDs, limit
+func (_m *PullRequestStorage) RecentReviewersByAuthors(ctx context.Context, authorIDs []string, limit int) (map[string][]string, error) {
+	ret := _m.Called(ctx, authorIDs, limit)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RecentReviewersByAuthors")
+	}
+
+	var r0 map[string][]string
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, []string, int) (map[string][]string, error)); ok {
+		return rf(ctx, authorIDs, limit)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, []string, int) map[string][]string); ok {
+		r0 = rf(ctx, authorIDs, limit)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(map[string][]string)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, []string, int) error); ok {
+		r1 = rf(ctx, authorIDs, limit)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// RemoveReviewer provides a mock function with given fields: ctx, pullRequestID, userID
+func (_m *PullRequestStorage) RemoveReviewer(ctx context.Context, pullRequestID string, userID string) error {
+	ret := _m.Called(ctx, pullRequestID, userID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for RemoveReviewer")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) error); ok {
+		r0 = rf(ctx, pullRequestID, userID)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// ReplaceReviewer provides a mock function with given fields: ctx, pullRequestID, oldID, newID, isFallback
+func (_m *PullRequestStorage) ReplaceReviewer(ctx context.Context, pullRequestID string, oldID string, newID string, isFallback bool) error {
+	ret := _m.Called(ctx, pullRequestID, oldID, newID, isFallback)
 
 	if len(ret) == 0 {
 		panic("no return value specified for ReplaceReviewer")
 	}
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(context.Context, string, string, string) error); ok {
-		r0 = rf(ctx, pullRequestID, oldID, newID)
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, string, bool) error); ok {
+		r0 = rf(ctx, pullRequestID, oldID, newID, isFallback)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// ReplaceReviewers provides a mock function with given fields: ctx, reassignments
+func (_m *PullRequestStorage) ReplaceReviewers(ctx context.Context, reassignments []domain.Reassignment) error {
+	ret := _m.Called(ctx, reassignments)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ReplaceReviewers")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, []domain.Reassignment) error); ok {
+		r0 = rf(ctx, reassignments)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// SetLabels provides a mock function with given fields: ctx, pullRequestID, labels
+func (_m *PullRequestStorage) SetLabels(ctx context.Context, pullRequestID string, labels []string) error {
+	ret := _m.Called(ctx, pullRequestID, labels)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SetLabels")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, []string) error); ok {
+		r0 = rf(ctx, pullRequestID, labels)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// SetReviewState provides a mock function with given fields: ctx, pullRequestID, userID, state, at
+func (_m *PullRequestStorage) SetReviewState(ctx context.Context, pullRequestID string, userID string, state domain.ReviewState, at time.Time) error {
+	ret := _m.Called(ctx, pullRequestID, userID, state, at)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SetReviewState")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, domain.ReviewState, time.Time) error); ok {
+		r0 = rf(ctx, pullRequestID, userID, state, at)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// UpdateMetadata provides a mock function with given fields: ctx, pullRequest
+func (_m *PullRequestStorage) UpdateMetadata(ctx context.Context, pullRequest domain.PullRequest) error {
+	ret := _m.Called(ctx, pullRequest)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateMetadata")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, domain.PullRequest) error); ok {
+		r0 = rf(ctx, pullRequest)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// UpdateStatus provides a mock function with given fields: ctx, pullRequestID, status
+func (_m *PullRequestStorage) UpdateStatus(ctx context.Context, pullRequestID string, status domain.PullRequestStatus) error {
+	ret := _m.Called(ctx, pullRequestID, status)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateStatus")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, domain.PullRequestStatus) error); ok {
+		r0 = rf(ctx, pullRequestID, status)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// UpdateStatusClosed provides a mock function with given fields: ctx, pullRequestID, closedAt
+func (_m *PullRequestStorage) UpdateStatusClosed(ctx context.Context, pullRequestID string, closedAt time.Time) error {
+	ret := _m.Called(ctx, pullRequestID, closedAt)
+
+	if len(ret) == 0 {
+		panic("no return value specified for UpdateStatusClosed")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, time.Time) error); ok {
+		r0 = rf(ctx, pullRequestID, closedAt)
 	} else {
 		r0 = ret.Error(0)
 	}
@@ -138,17 +594,17 @@ func (_m *PullRequestStorage) ReplaceReviewer(ctx context.Context, pullRequestID
 	return r0
 }
 
-// UpdateStatusMerged provides a mock function with given fields: ctx, pullRequestID, mergedAt
-func (_m *PullRequestStorage) UpdateStatusMerged(ctx context.Context, pullRequestID string, mergedAt *time.Time) error {
-	ret := _m.Called(ctx, pullRequestID, mergedAt)
+// UpdateStatusMerged provides a mock function with given fields: ctx, pullRequestID, mergedAt, override
+func (_m *PullRequestStorage) UpdateStatusMerged(ctx context.Context, pullRequestID string, mergedAt *time.Time, override *domain.MergeOverride) error {
+	ret := _m.Called(ctx, pullRequestID, mergedAt, override)
 
 	if len(ret) == 0 {
 		panic("no return value specified for UpdateStatusMerged")
 	}
 
 	var r0 error
-	if rf, ok := ret.Get(0).(func(context.Context, string, *time.Time) error); ok {
-		r0 = rf(ctx, pullRequestID, mergedAt)
+	if rf, ok := ret.Get(0).(func(context.Context, string, *time.Time, *domain.MergeOverride) error); ok {
+		r0 = rf(ctx, pullRequestID, mergedAt, override)
 	} else {
 		r0 = ret.Error(0)
 	}
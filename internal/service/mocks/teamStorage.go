@@ -32,6 +32,62 @@ func (_m *TeamStorage) CreateWithMembers(ctx context.Context, team domain.Team)
 	return r0
 }
 
+// GetCodeOwners provides a mock function with given fields: ctx, teamName
+func (_m *TeamStorage) GetCodeOwners(ctx context.Context, teamName string) (string, error) {
+	ret := _m.Called(ctx, teamName)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetCodeOwners")
+	}
+
+	var r0 string
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (string, error)); ok {
+		return rf(ctx, teamName)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) string); ok {
+		r0 = rf(ctx, teamName)
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, teamName)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetTeamSettings provides a mock function with given fields: ctx, teamName
+func (_m *TeamStorage) GetTeamSettings(ctx context.Context, teamName string) (domain.TeamSettings, error) {
+	ret := _m.Called(ctx, teamName)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetTeamSettings")
+	}
+
+	var r0 domain.TeamSettings
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) (domain.TeamSettings, error)); ok {
+		return rf(ctx, teamName)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) domain.TeamSettings); ok {
+		r0 = rf(ctx, teamName)
+	} else {
+		r0 = ret.Get(0).(domain.TeamSettings)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, teamName)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetWithMembers provides a mock function with given fields: ctx, teamName
 func (_m *TeamStorage) GetWithMembers(ctx context.Context, teamName string) (*domain.Team, error) {
 	ret := _m.Called(ctx, teamName)
@@ -62,6 +118,42 @@ func (_m *TeamStorage) GetWithMembers(ctx context.Context, teamName string) (*do
 	return r0, r1
 }
 
+// SaveCodeOwners provides a mock function with given fields: ctx, teamName, rules
+func (_m *TeamStorage) SaveCodeOwners(ctx context.Context, teamName string, rules string) error {
+	ret := _m.Called(ctx, teamName, rules)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SaveCodeOwners")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string) error); ok {
+		r0 = rf(ctx, teamName, rules)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// SaveTeamSettings provides a mock function with given fields: ctx, settings
+func (_m *TeamStorage) SaveTeamSettings(ctx context.Context, settings domain.TeamSettings) error {
+	ret := _m.Called(ctx, settings)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SaveTeamSettings")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, domain.TeamSettings) error); ok {
+		r0 = rf(ctx, settings)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // TeamExists provides a mock function with given fields: ctx, teamName
 func (_m *TeamStorage) TeamExists(ctx context.Context, teamName string) (bool, error) {
 	ret := _m.Called(ctx, teamName)
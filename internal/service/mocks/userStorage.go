@@ -7,6 +7,8 @@ import (
 	context "context"
 
 	mock "github.com/stretchr/testify/mock"
+
+	time "time"
 )
 
 // UserStorage is an autogenerated mock type for the UserStorage type
@@ -14,6 +16,80 @@ type UserStorage struct {
 	mock.Mock
 }
 
+// AddAbsence provides a mock function with given fields: ctx, absence
+func (_m *UserStorage) AddAbsence(ctx context.Context, absence domain.Absence) (int64, error) {
+	ret := _m.Called(ctx, absence)
+
+	if len(ret) == 0 {
+		panic("no return value specified for AddAbsence")
+	}
+
+	var r0 int64
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, domain.Absence) (int64, error)); ok {
+		return rf(ctx, absence)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, domain.Absence) int64); ok {
+		r0 = rf(ctx, absence)
+	} else {
+		r0 = ret.Get(0).(int64)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, domain.Absence) error); ok {
+		r1 = rf(ctx, absence)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// CancelAbsence provides a mock function with given fields: ctx, absenceID, cancelledAt
+func (_m *UserStorage) CancelAbsence(ctx context.Context, absenceID int64, cancelledAt time.Time) error {
+	ret := _m.Called(ctx, absenceID, cancelledAt)
+
+	if len(ret) == 0 {
+		panic("no return value specified for CancelAbsence")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64, time.Time) error); ok {
+		r0 = rf(ctx, absenceID, cancelledAt)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// GetAbsenceByID provides a mock function with given fields: ctx, absenceID
+func (_m *UserStorage) GetAbsenceByID(ctx context.Context, absenceID int64) (domain.Absence, error) {
+	ret := _m.Called(ctx, absenceID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetAbsenceByID")
+	}
+
+	var r0 domain.Absence
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, int64) (domain.Absence, error)); ok {
+		return rf(ctx, absenceID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, int64) domain.Absence); ok {
+		r0 = rf(ctx, absenceID)
+	} else {
+		r0 = ret.Get(0).(domain.Absence)
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, int64) error); ok {
+		r1 = rf(ctx, absenceID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetUserByID provides a mock function with given fields: ctx, userID
 func (_m *UserStorage) GetUserByID(ctx context.Context, userID string) (*domain.User, error) {
 	ret := _m.Called(ctx, userID)
@@ -44,6 +120,66 @@ func (_m *UserStorage) GetUserByID(ctx context.Context, userID string) (*domain.
 	return r0, r1
 }
 
+// GetUsersByIDs provides a mock function with given fields: ctx, userIDs
+func (_m *UserStorage) GetUsersByIDs(ctx context.Context, userIDs []string) ([]domain.User, error) {
+	ret := _m.Called(ctx, userIDs)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetUsersByIDs")
+	}
+
+	var r0 []domain.User
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, []string) ([]domain.User, error)); ok {
+		return rf(ctx, userIDs)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, []string) []domain.User); ok {
+		r0 = rf(ctx, userIDs)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]domain.User)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, []string) error); ok {
+		r1 = rf(ctx, userIDs)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// ListAbsences provides a mock function with given fields: ctx, userID
+func (_m *UserStorage) ListAbsences(ctx context.Context, userID string) ([]domain.Absence, error) {
+	ret := _m.Called(ctx, userID)
+
+	if len(ret) == 0 {
+		panic("no return value specified for ListAbsences")
+	}
+
+	var r0 []domain.Absence
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, string) ([]domain.Absence, error)); ok {
+		return rf(ctx, userID)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, string) []domain.Absence); ok {
+		r0 = rf(ctx, userID)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]domain.Absence)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, string) error); ok {
+		r1 = rf(ctx, userID)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // ListActiveUserByTeam provides a mock function with given fields: ctx, teamName
 func (_m *UserStorage) ListActiveUserByTeam(ctx context.Context, teamName string) ([]domain.User, error) {
 	ret := _m.Called(ctx, teamName)
@@ -92,6 +228,96 @@ func (_m *UserStorage) SetIsActive(ctx context.Context, userID string, isActive
 	return r0
 }
 
+// SetIsActiveMany provides a mock function with given fields: ctx, userIDs, isActive
+func (_m *UserStorage) SetIsActiveMany(ctx context.Context, userIDs []string, isActive bool) error {
+	ret := _m.Called(ctx, userIDs, isActive)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SetIsActiveMany")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, []string, bool) error); ok {
+		r0 = rf(ctx, userIDs, isActive)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// SetMaxOpenReviews provides a mock function with given fields: ctx, userID, maxOpenReviews
+func (_m *UserStorage) SetMaxOpenReviews(ctx context.Context, userID string, maxOpenReviews *int) error {
+	ret := _m.Called(ctx, userID, maxOpenReviews)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SetMaxOpenReviews")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, *int) error); ok {
+		r0 = rf(ctx, userID, maxOpenReviews)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// SetSeniority provides a mock function with given fields: ctx, userID, seniority
+func (_m *UserStorage) SetSeniority(ctx context.Context, userID string, seniority domain.Seniority) error {
+	ret := _m.Called(ctx, userID, seniority)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SetSeniority")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, domain.Seniority) error); ok {
+		r0 = rf(ctx, userID, seniority)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// SetTags provides a mock function with given fields: ctx, userID, tags
+func (_m *UserStorage) SetTags(ctx context.Context, userID string, tags []string) error {
+	ret := _m.Called(ctx, userID, tags)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SetTags")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, []string) error); ok {
+		r0 = rf(ctx, userID, tags)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// SetWorkingHours provides a mock function with given fields: ctx, userID, timezone, hours
+func (_m *UserStorage) SetWorkingHours(ctx context.Context, userID string, timezone string, hours *domain.WorkingHours) error {
+	ret := _m.Called(ctx, userID, timezone, hours)
+
+	if len(ret) == 0 {
+		panic("no return value specified for SetWorkingHours")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context, string, string, *domain.WorkingHours) error); ok {
+		r0 = rf(ctx, userID, timezone, hours)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
 // NewUserStorage creates a new instance of UserStorage. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
 // The first argument is typically a *testing.T value.
 func NewUserStorage(t interface {
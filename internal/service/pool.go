@@ -0,0 +1,251 @@
+package service
+
+import (
+	"context"
+	"hash/fnv"
+	"math/rand/v2"
+	"slices"
+	"time"
+
+	"avito/internal/domain"
+)
+
+// candidatePool caches team settings, active members and open review counts
+// for one operation. Review moves planned but not written to storage yet are
+// kept in delta, so load and capacity stay correct inside a batch.
+type candidatePool struct {
+	teamStore TeamStorage
+	userStore UserStorage
+	prStore   PullRequestStorage
+
+	settings map[string]domain.TeamSettings
+	members  map[string][]domain.User
+	load     map[string]int
+	delta    map[string]int
+
+	seed    uint64
+	rng     *rand.Rand
+	steps   []domain.SelectionStep
+	reasons map[string]domain.ChoiceReason
+	dryRun  bool // selectors must not change their state
+
+	// reviewers and authors of the batch, their users and recent reviewers are
+	// loaded with one query each on the first need
+	batchUsers   []string
+	batchAuthors []string
+	users        map[string]domain.User
+	usersLoaded  bool
+	recent       map[string][]string
+	recentLimit  int
+	recentLoaded bool
+}
+
+func (s *Service) newCandidatePool() *candidatePool {
+	pool := &candidatePool{
+		teamStore: s.teamStore,
+		userStore: s.userStore,
+		prStore:   s.prStore,
+		settings:  make(map[string]domain.TeamSettings),
+		members:   make(map[string][]domain.User),
+		load:      make(map[string]int),
+		delta:     make(map[string]int),
+		users:     make(map[string]domain.User),
+		recent:    make(map[string][]string),
+		seed:      s.nextSeed(),
+	}
+	pool.begin("")
+	return pool
+}
+
+// batch registers pull requests the operation goes through, so reviewers and
+// authors of all of them are loaded at once instead of one query per PR.
+func (p *candidatePool) batch(prs []domain.PullRequest) {
+	for _, pr := range prs {
+		p.batchUsers = appendMissing(p.batchUsers, pr.AssignedReviewers...)
+		p.batchAuthors = appendMissing(p.batchAuthors, pr.AuthorID)
+	}
+}
+
+// begin starts selection for the pull request: the random source is derived
+// from the seed of the pool and prID, so every PR of a batch is reproducible alone.
+func (p *candidatePool) begin(prID string) {
+	p.rng = rand.New(rand.NewPCG(p.seed, selectionStream(prID)))
+	p.steps = nil
+	p.reasons = make(map[string]domain.ChoiceReason)
+}
+
+func selectionStream(prID string) uint64 {
+	h := fnv.New64a()
+	_, _ = h.Write([]byte(prID))
+	return h.Sum64()
+}
+
+func (p *candidatePool) record(in SelectInput, chosen []string) {
+	p.steps = append(p.steps, domain.SelectionStep{
+		TeamName:   in.TeamName,
+		Strategy:   in.Strategy,
+		Candidates: userIDs(in.Candidates),
+		Quantity:   in.Quantity,
+		Chosen:     slices.Clone(chosen),
+		Inputs:     in.Inputs,
+	})
+}
+
+// choose records why reviewers were chosen, the first reason of a reviewer wins.
+func (p *candidatePool) choose(reason domain.ChoiceReason, ids ...string) {
+	for _, id := range ids {
+		if _, ok := p.reasons[id]; !ok {
+			p.reasons[id] = reason
+		}
+	}
+}
+
+// audit returns selection steps recorded since begin.
+func (p *candidatePool) audit(prID string, action domain.AssignmentAction, now time.Time) domain.AssignmentAudit {
+	return domain.AssignmentAudit{
+		PullRequestID: prID,
+		Action:        action,
+		Seed:          p.seed,
+		Steps:         p.steps,
+		CreatedAt:     now,
+	}
+}
+
+func (p *candidatePool) teamSettings(ctx context.Context, teamName string) (domain.TeamSettings, error) {
+	if settings, ok := p.settings[teamName]; ok {
+		return settings, nil
+	}
+
+	settings, err := p.teamStore.GetTeamSettings(ctx, teamName)
+	if err != nil {
+		return domain.TeamSettings{}, err
+	}
+	p.settings[teamName] = settings
+	return settings, nil
+}
+
+func (p *candidatePool) activeMembers(ctx context.Context, teamName string) ([]domain.User, error) {
+	if members, ok := p.members[teamName]; ok {
+		return members, nil
+	}
+
+	members, err := p.userStore.ListActiveUserByTeam(ctx, teamName)
+	if err != nil {
+		return nil, err
+	}
+	p.members[teamName] = members
+	return members, nil
+}
+
+// cachedUser looks for the user among active members loaded by the pool.
+func (p *candidatePool) cachedUser(userID string) (domain.User, bool) {
+	for _, members := range p.members {
+		for _, user := range members {
+			if user.ID == userID {
+				return user, true
+			}
+		}
+	}
+	return domain.User{}, false
+}
+
+// user returns the user from active members loaded by the pool or from storage,
+// so inactive and absent users are found too. The first reviewer of the batch
+// missing in the pool loads all reviewers of the batch.
+func (p *candidatePool) user(ctx context.Context, userID string) (domain.User, error) {
+	if user, ok := p.cachedUser(userID); ok {
+		return user, nil
+	}
+	if user, ok := p.users[userID]; ok {
+		return user, nil
+	}
+
+	if !p.usersLoaded && slices.Contains(p.batchUsers, userID) {
+		p.usersLoaded = true
+		users, err := p.userStore.GetUsersByIDs(ctx, p.batchUsers)
+		if err != nil {
+			return domain.User{}, err
+		}
+		for _, user := range users {
+			p.users[user.ID] = user
+		}
+		if user, ok := p.users[userID]; ok {
+			return user, nil
+		}
+	}
+
+	user, err := p.userStore.GetUserByID(ctx, userID)
+	if err != nil {
+		return domain.User{}, err
+	}
+	p.users[userID] = *user
+	return *user, nil
+}
+
+// RecentReviewers lists recent reviewers of the author once per pool, the first
+// author of the batch loads all authors of the batch.
+func (p *candidatePool) RecentReviewers(ctx context.Context, authorID string, limit int) ([]string, error) {
+	if limit != p.recentLimit {
+		p.recent = make(map[string][]string)
+		p.recentLimit = limit
+		p.recentLoaded = false
+	}
+	if recent, ok := p.recent[authorID]; ok {
+		return recent, nil
+	}
+
+	if p.recentLoaded || !slices.Contains(p.batchAuthors, authorID) {
+		recent, err := p.prStore.RecentReviewers(ctx, authorID, limit)
+		if err != nil {
+			return nil, err
+		}
+		p.recent[authorID] = recent
+		return recent, nil
+	}
+
+	p.recentLoaded = true
+	recent, err := p.prStore.RecentReviewersByAuthors(ctx, p.batchAuthors, limit)
+	if err != nil {
+		return nil, err
+	}
+	for _, id := range p.batchAuthors {
+		p.recent[id] = recent[id]
+	}
+	return p.recent[authorID], nil
+}
+
+// CountOpenReviews counts in storage only users not counted before in this pool.
+func (p *candidatePool) CountOpenReviews(ctx context.Context, userIDs []string) (map[string]int, error) {
+	unknown := make([]string, 0)
+	for _, id := range userIDs {
+		if _, ok := p.load[id]; !ok {
+			unknown = append(unknown, id)
+		}
+	}
+
+	if len(unknown) > 0 {
+		counted, err := p.prStore.CountOpenReviews(ctx, unknown)
+		if err != nil {
+			return nil, err
+		}
+		for _, id := range unknown {
+			p.load[id] = counted[id]
+		}
+	}
+
+	out := make(map[string]int, len(userIDs))
+	for _, id := range userIDs {
+		out[id] = p.load[id] + p.delta[id]
+	}
+	return out, nil
+}
+
+// moveReview records planned review move, from or to may be empty.
+func (p *candidatePool) moveReview(from, to string) {
+	if from != "" {
+		p.delta[from]--
+	}
+	if to != "" {
+		p.delta[to]++
+	}
+}
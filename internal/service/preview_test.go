@@ -0,0 +1,56 @@
+package service
+
+import (
+	"avito/internal/domain"
+	"avito/internal/service/mocks"
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestService_PreviewPullRequest_WritesNothing(t *testing.T) {
+	ctx := context.Background()
+
+	prStore := mocks.NewPullRequestStorage(t)
+	userStore := mocks.NewUserStorage(t)
+	teamStore := mocks.NewTeamStorage(t)
+
+	author := domain.User{ID: "u1", TeamName: "team-A", IsActive: true}
+	members := []domain.User{
+		author,
+		{ID: "u2", TeamName: "team-A", IsActive: true},
+		{ID: "u3", TeamName: "team-A", IsActive: true},
+		{ID: "u4", TeamName: "team-A", IsActive: true},
+	}
+
+	userStore.
+		On("GetUserByID", ctx, "u1").
+		Return(&author, nil).Twice()
+
+	teamStore.
+		On("GetTeamSettings", ctx, "team-A").
+		Return(defaultSettings, nil).Twice()
+
+	userStore.
+		On("ListActiveUserByTeam", ctx, "team-A").
+		Return(members, nil).Twice()
+
+	teamStore.
+		On("GetWithMembers", ctx, "team-A").
+		Return(&domain.Team{Name: "team-A", Members: members}, nil).Twice()
+
+	svc := NewService(teamStore, userStore, prStore, &mockTxManager{}, NewRoundRobinSelector(), nil)
+
+	first, err := svc.PreviewPullRequest(ctx, domain.PullRequestCreate{AuthorID: "u1"})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"u2", "u3"}, first.PullRequest.AssignedReviewers)
+	require.Len(t, first.Decision.Teams, 1)
+	assert.Equal(t, []string{"u2", "u3", "u4"}, first.Decision.Teams[0].Eligible)
+
+	// round-robin rotation did not move
+	second, err := svc.PreviewPullRequest(ctx, domain.PullRequestCreate{AuthorID: "u1"})
+	require.NoError(t, err)
+	assert.Equal(t, first.PullRequest.AssignedReviewers, second.PullRequest.AssignedReviewers)
+}
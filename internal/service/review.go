@@ -0,0 +1,69 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"avito/internal/domain"
+)
+
+// SubmitReview stores the verdict of the assigned reviewer, a later verdict replaces the earlier one.
+func (s *Service) SubmitReview(ctx context.Context, prID, userID string, state domain.ReviewState) (domain.PullRequest, error) {
+	state = domain.ReviewState(strings.ToUpper(string(state)))
+	if !state.IsVerdict() {
+		return domain.PullRequest{}, fmt.Errorf("%w: unknown verdict %q", domain.ErrInvalidInput, state)
+	}
+
+	var result domain.PullRequest
+
+	err := s.tx.WithTx(ctx, func(ctx context.Context) error {
+		pr, err := s.prStore.GetPullRequestByIDForUpdate(ctx, prID)
+		if err != nil {
+			return err
+		}
+		if err := requireOpen(pr); err != nil {
+			return err
+		}
+		if !slices.Contains(pr.AssignedReviewers, userID) {
+			return domain.ErrNotAssigned
+		}
+
+		now := time.Now().UTC()
+		if err := s.prStore.SetReviewState(ctx, prID, userID, state, now); err != nil {
+			return err
+		}
+
+		if pr.Reviews == nil {
+			pr.Reviews = make(map[string]domain.Review, 1)
+		}
+		pr.Reviews[userID] = domain.Review{State: state, UpdatedAt: &now}
+		result = pr
+		return nil
+	})
+
+	return result, err
+}
+
+// checkApprovals returns ErrNotApproved when PR breaks the approval rule of the team.
+func checkApprovals(settings domain.TeamSettings, pr domain.PullRequest) error {
+	if settings.RequiredApprovals == 0 {
+		return nil
+	}
+
+	approvals := 0
+	for _, id := range pr.AssignedReviewers {
+		switch pr.ReviewOf(id).State {
+		case domain.ReviewApproved:
+			approvals++
+		case domain.ReviewChangesRequested:
+			return fmt.Errorf("%w: %s requested changes", domain.ErrNotApproved, id)
+		}
+	}
+	if approvals < settings.RequiredApprovals {
+		return fmt.Errorf("%w: %d of %d required approvals", domain.ErrNotApproved, approvals, settings.RequiredApprovals)
+	}
+	return nil
+}
@@ -0,0 +1,175 @@
+package service
+
+import (
+	"avito/internal/domain"
+	"avito/internal/service/mocks"
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestService_SubmitReview(t *testing.T) {
+	ctx := context.Background()
+
+	open := domain.PullRequest{ID: "pr-1", AuthorID: "author", Status: domain.PRStatusOpen, AssignedReviewers: []string{"u1", "u2"}}
+
+	tests := []struct {
+		name    string
+		pr      *domain.PullRequest
+		userID  string
+		state   domain.ReviewState
+		wantErr error
+	}{
+		{
+			name:   "approve",
+			pr:     &open,
+			userID: "u1",
+			state:  "approved",
+		},
+		{
+			name:    "pending_is_not_a_verdict",
+			userID:  "u1",
+			state:   domain.ReviewPending,
+			wantErr: domain.ErrInvalidInput,
+		},
+		{
+			name:    "not_assigned",
+			pr:      &open,
+			userID:  "u3",
+			state:   domain.ReviewCommented,
+			wantErr: domain.ErrNotAssigned,
+		},
+		{
+			name:    "merged",
+			pr:      &domain.PullRequest{ID: "pr-1", Status: domain.PRStatusMerged, AssignedReviewers: []string{"u1"}},
+			userID:  "u1",
+			state:   domain.ReviewChangesRequested,
+			wantErr: domain.ErrPRMerged,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			prStore := mocks.NewPullRequestStorage(t)
+
+			if tt.pr != nil {
+				prStore.
+					On("GetPullRequestByIDForUpdate", ctx, "pr-1").
+					Return(*tt.pr, nil).Once()
+			}
+			if tt.wantErr == nil {
+				prStore.
+					On("SetReviewState", ctx, "pr-1", tt.userID, domain.ReviewApproved, mock.AnythingOfType("time.Time")).
+					Return(nil).Once()
+			}
+
+			svc := NewService(mocks.NewTeamStorage(t), mocks.NewUserStorage(t), prStore, &mockTxManager{}, NewRandomSelector(), nil)
+
+			got, err := svc.SubmitReview(ctx, "pr-1", tt.userID, tt.state)
+			if tt.wantErr != nil {
+				require.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+
+			review := got.ReviewOf(tt.userID)
+			assert.Equal(t, domain.ReviewApproved, review.State)
+			assert.NotNil(t, review.UpdatedAt)
+			assert.Equal(t, domain.ReviewPending, got.ReviewOf("u2").State)
+		})
+	}
+}
+
+func TestService_MergePullRequest_RequiredApprovals(t *testing.T) {
+	ctx := context.Background()
+
+	settings := defaultSettings
+	settings.RequiredApprovals = 2
+
+	approved := domain.Review{State: domain.ReviewApproved}
+	override := &domain.MergeOverride{By: "admin", Reason: "hotfix"}
+
+	tests := []struct {
+		name         string
+		reviews      map[string]domain.Review
+		override     *domain.MergeOverride
+		wantErr      error
+		wantOverride *domain.MergeOverride
+	}{
+		{
+			name:    "approved",
+			reviews: map[string]domain.Review{"u1": approved, "u2": approved},
+		},
+		{
+			name:    "not_enough_approvals",
+			reviews: map[string]domain.Review{"u1": approved},
+			wantErr: domain.ErrNotApproved,
+		},
+		{
+			name:    "changes_requested",
+			reviews: map[string]domain.Review{"u1": approved, "u2": approved, "u3": {State: domain.ReviewChangesRequested}},
+			wantErr: domain.ErrNotApproved,
+		},
+		{
+			name:         "override",
+			reviews:      map[string]domain.Review{"u1": approved},
+			override:     override,
+			wantOverride: override,
+		},
+		{
+			name:     "override_not_needed",
+			reviews:  map[string]domain.Review{"u1": approved, "u2": approved},
+			override: override,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			teamStore := mocks.NewTeamStorage(t)
+			userStore := mocks.NewUserStorage(t)
+			prStore := mocks.NewPullRequestStorage(t)
+
+			prStore.
+				On("GetPullRequestByIDForUpdate", ctx, "pr-1").
+				Return(domain.PullRequest{
+					ID:                "pr-1",
+					AuthorID:          "author",
+					Status:            domain.PRStatusOpen,
+					AssignedReviewers: []string{"u1", "u2", "u3"},
+					Reviews:           tt.reviews,
+				}, nil).Once()
+
+			userStore.
+				On("GetUserByID", ctx, "author").
+				Return(&domain.User{ID: "author", TeamName: "team-A"}, nil).Once()
+
+			teamStore.
+				On("GetTeamSettings", ctx, "team-A").
+				Return(settings, nil).Once()
+
+			if tt.wantErr == nil {
+				prStore.
+					On("UpdateStatusMerged", ctx, "pr-1", mock.AnythingOfType("*time.Time"), tt.wantOverride).
+					Return(nil).Once()
+
+				prStore.
+					On("ListByStatus", ctx, domain.PRStatusNeedsReviewers, staffBatchSize).
+					Return([]domain.PullRequest{}, nil).Once()
+			}
+
+			svc := NewService(teamStore, userStore, prStore, &mockTxManager{}, NewRandomSelector(), nil)
+
+			got, err := svc.MergePullRequest(ctx, "pr-1", tt.override)
+			if tt.wantErr != nil {
+				require.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, domain.PRStatusMerged, got.Status)
+			assert.Equal(t, tt.wantOverride, got.MergeOverride)
+		})
+	}
+}
@@ -0,0 +1,216 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	"avito/internal/domain"
+)
+
+// AddReviewer assigns the user to the open PR. The user must be an active, not absent member
+// of the author's team or its backup teams, max_open_reviews is not checked for manual choice.
+// NEEDS_REVIEWERS PR becomes OPEN when it gets MinReviewers reviewers.
+func (s *Service) AddReviewer(ctx context.Context, prID, userID string) (domain.PullRequest, error) {
+	var result domain.PullRequest
+
+	err := s.tx.WithTx(ctx, func(ctx context.Context) error {
+		pr, err := s.prStore.GetPullRequestByIDForUpdate(ctx, prID)
+		if err != nil {
+			return err
+		}
+		if err := requireOpen(pr); err != nil {
+			return err
+		}
+
+		settings, err := s.authorSettings(ctx, pr)
+		if err != nil {
+			return err
+		}
+		if len(pr.AssignedReviewers) >= settings.MaxReviewers {
+			return fmt.Errorf("%w: pull request already has max_reviewers reviewers", domain.ErrInvalidInput)
+		}
+
+		pool := s.newCandidatePool()
+		user, err := s.manualReviewer(ctx, pool, pr, settings, userID)
+		if err != nil {
+			return err
+		}
+		if err := s.checkManualSeniority(ctx, pool, settings, append(slices.Clone(pr.AssignedReviewers), userID)); err != nil {
+			return err
+		}
+
+		isFallback := user.TeamName != settings.TeamName
+		if err := s.prStore.AddReviewer(ctx, prID, userID, isFallback, false); err != nil {
+			return err
+		}
+		pr.AssignedReviewers = append(pr.AssignedReviewers, userID)
+		if isFallback {
+			pr.FallbackReviewers = append(pr.FallbackReviewers, userID)
+		}
+
+		if pr.Status == domain.PRStatusNeedsReviewers && len(pr.AssignedReviewers) >= settings.MinReviewers {
+			if err := s.prStore.UpdateStatus(ctx, prID, domain.PRStatusOpen); err != nil {
+				return err
+			}
+			pr.Status = domain.PRStatusOpen
+		}
+
+		result = pr
+		return nil
+	})
+
+	return result, err
+}
+
+// RemoveReviewer unassigns the reviewer without replacement, mandatory reviewers
+// are removed only with force. Understaffed PR is topped up later by backfill.
+func (s *Service) RemoveReviewer(ctx context.Context, prID, userID string, force bool) (domain.PullRequest, error) {
+	var result domain.PullRequest
+
+	err := s.tx.WithTx(ctx, func(ctx context.Context) error {
+		pr, err := s.prStore.GetPullRequestByIDForUpdate(ctx, prID)
+		if err != nil {
+			return err
+		}
+		if err := requireOpen(pr); err != nil {
+			return err
+		}
+		if !slices.Contains(pr.AssignedReviewers, userID) {
+			return domain.ErrNotAssigned
+		}
+		if !force && slices.Contains(pr.MandatoryReviewers, userID) {
+			return domain.ErrMandatoryReviewer
+		}
+
+		isRemoved := func(id string) bool { return id == userID }
+		rest := slices.DeleteFunc(slices.Clone(pr.AssignedReviewers), isRemoved)
+
+		settings, err := s.authorSettings(ctx, pr)
+		if err != nil {
+			return err
+		}
+		if err := s.checkManualSeniority(ctx, s.newCandidatePool(), settings, rest); err != nil {
+			return err
+		}
+
+		if err := s.prStore.RemoveReviewer(ctx, prID, userID); err != nil {
+			return err
+		}
+
+		pr.AssignedReviewers = rest
+		pr.FallbackReviewers = slices.DeleteFunc(pr.FallbackReviewers, isRemoved)
+		pr.MandatoryReviewers = slices.DeleteFunc(pr.MandatoryReviewers, isRemoved)
+		result = pr
+		return nil
+	})
+
+	return result, err
+}
+
+// ReassignReviewerTo replaces oldUserID with newUserID chosen by a person, newUserID
+// is validated like in AddReviewer. Mandatory reviewers are replaced only with force.
+func (s *Service) ReassignReviewerTo(ctx context.Context, prID, oldUserID, newUserID string, force bool) (domain.PullRequest, error) {
+	var result domain.PullRequest
+
+	err := s.tx.WithTx(ctx, func(ctx context.Context) error {
+		pr, err := s.prStore.GetPullRequestByIDForUpdate(ctx, prID)
+		if err != nil {
+			return err
+		}
+		if err := requireOpen(pr); err != nil {
+			return err
+		}
+		if !slices.Contains(pr.AssignedReviewers, oldUserID) {
+			return domain.ErrNotAssigned
+		}
+		if !force && slices.Contains(pr.MandatoryReviewers, oldUserID) {
+			return domain.ErrMandatoryReviewer
+		}
+
+		settings, err := s.authorSettings(ctx, pr)
+		if err != nil {
+			return err
+		}
+
+		pool := s.newCandidatePool()
+		user, err := s.manualReviewer(ctx, pool, pr, settings, newUserID)
+		if err != nil {
+			return err
+		}
+
+		isOld := func(id string) bool { return id == oldUserID }
+		reviewers := append(slices.DeleteFunc(slices.Clone(pr.AssignedReviewers), isOld), newUserID)
+		if err := s.checkManualSeniority(ctx, pool, settings, reviewers); err != nil {
+			return err
+		}
+
+		isFallback := user.TeamName != settings.TeamName
+		if err := s.prStore.ReplaceReviewer(ctx, prID, oldUserID, newUserID, isFallback); err != nil {
+			return err
+		}
+
+		for i, id := range pr.AssignedReviewers {
+			if id == oldUserID {
+				pr.AssignedReviewers[i] = newUserID
+			}
+		}
+		pr.FallbackReviewers = slices.DeleteFunc(pr.FallbackReviewers, isOld)
+		pr.MandatoryReviewers = slices.DeleteFunc(pr.MandatoryReviewers, isOld)
+		if isFallback {
+			pr.FallbackReviewers = append(pr.FallbackReviewers, newUserID)
+		}
+
+		result = pr
+		return nil
+	})
+
+	return result, err
+}
+
+func (s *Service) authorSettings(ctx context.Context, pr domain.PullRequest) (domain.TeamSettings, error) {
+	author, err := s.userStore.GetUserByID(ctx, pr.AuthorID)
+	if err != nil {
+		return domain.TeamSettings{}, err
+	}
+	return s.teamStore.GetTeamSettings(ctx, author.TeamName)
+}
+
+// manualReviewer returns the user when they may be assigned to the PR by hand.
+func (s *Service) manualReviewer(ctx context.Context, pool *candidatePool, pr domain.PullRequest, settings domain.TeamSettings, userID string) (domain.User, error) {
+	if userID == pr.AuthorID {
+		return domain.User{}, fmt.Errorf("%w: author can not review own pull request", domain.ErrInvalidInput)
+	}
+	if slices.Contains(pr.AssignedReviewers, userID) {
+		return domain.User{}, domain.ErrAlreadyAssigned
+	}
+
+	user, err := s.userStore.GetUserByID(ctx, userID)
+	if err != nil {
+		return domain.User{}, err
+	}
+	if user.TeamName != settings.TeamName && !slices.Contains(settings.BackupTeams, user.TeamName) {
+		return domain.User{}, fmt.Errorf("%w: user %q is not a member of team %q or its backup teams", domain.ErrReviewerUnavailable, userID, settings.TeamName)
+	}
+
+	members, err := pool.activeMembers(ctx, user.TeamName)
+	if err != nil {
+		return domain.User{}, err
+	}
+	if !slices.ContainsFunc(members, func(member domain.User) bool { return member.ID == userID }) {
+		return domain.User{}, fmt.Errorf("%w: user %q is inactive or absent", domain.ErrReviewerUnavailable, userID)
+	}
+	return *user, nil
+}
+
+// checkManualSeniority checks reviewers chosen by a person against the seniority policy.
+func (s *Service) checkManualSeniority(ctx context.Context, pool *candidatePool, settings domain.TeamSettings, reviewers []string) error {
+	if !hasSeniorityPolicy(settings) {
+		return nil
+	}
+	users, err := reviewerUsers(ctx, pool, reviewers)
+	if err != nil {
+		return err
+	}
+	return checkSeniority(settings, users)
+}
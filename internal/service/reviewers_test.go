@@ -0,0 +1,209 @@
+package service
+
+import (
+	"avito/internal/domain"
+	"avito/internal/service/mocks"
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestService_AddReviewer(t *testing.T) {
+	ctx := context.Background()
+
+	tests := []struct {
+		name       string
+		pr         domain.PullRequest
+		userID     string
+		userTeam   string
+		active     []domain.User
+		wantErr    error
+		wantStatus domain.PullRequestStatus
+	}{
+		{
+			name:       "staffs_waiting_pr",
+			pr:         domain.PullRequest{ID: "pr-1", AuthorID: "author", Status: domain.PRStatusNeedsReviewers, AssignedReviewers: []string{"u1"}},
+			userID:     "u2",
+			userTeam:   "team-A",
+			active:     []domain.User{{ID: "u1", TeamName: "team-A"}, {ID: "u2", TeamName: "team-A"}},
+			wantStatus: domain.PRStatusOpen,
+		},
+		{
+			name:    "merged",
+			pr:      domain.PullRequest{ID: "pr-1", AuthorID: "author", Status: domain.PRStatusMerged},
+			userID:  "u2",
+			wantErr: domain.ErrPRMerged,
+		},
+		{
+			name:     "inactive_or_absent",
+			pr:       domain.PullRequest{ID: "pr-1", AuthorID: "author", Status: domain.PRStatusOpen},
+			userID:   "u2",
+			userTeam: "team-A",
+			active:   []domain.User{{ID: "u1", TeamName: "team-A"}},
+			wantErr:  domain.ErrReviewerUnavailable,
+		},
+		{
+			name:     "foreign_team",
+			pr:       domain.PullRequest{ID: "pr-1", AuthorID: "author", Status: domain.PRStatusOpen},
+			userID:   "u2",
+			userTeam: "team-B",
+			wantErr:  domain.ErrReviewerUnavailable,
+		},
+		{
+			name:    "already_assigned",
+			pr:      domain.PullRequest{ID: "pr-1", AuthorID: "author", Status: domain.PRStatusOpen, AssignedReviewers: []string{"u2"}},
+			userID:  "u2",
+			wantErr: domain.ErrAlreadyAssigned,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			teamStore := mocks.NewTeamStorage(t)
+			userStore := mocks.NewUserStorage(t)
+			prStore := mocks.NewPullRequestStorage(t)
+
+			prStore.
+				On("GetPullRequestByIDForUpdate", ctx, "pr-1").
+				Return(tt.pr, nil).Once()
+
+			if tt.pr.Status != domain.PRStatusMerged {
+				userStore.
+					On("GetUserByID", ctx, "author").
+					Return(&domain.User{ID: "author", TeamName: "team-A"}, nil).Once()
+
+				teamStore.
+					On("GetTeamSettings", ctx, "team-A").
+					Return(defaultSettings, nil).Once()
+			}
+			if tt.userTeam != "" {
+				userStore.
+					On("GetUserByID", ctx, tt.userID).
+					Return(&domain.User{ID: tt.userID, TeamName: tt.userTeam}, nil).Once()
+			}
+			if tt.active != nil {
+				userStore.
+					On("ListActiveUserByTeam", ctx, tt.userTeam).
+					Return(tt.active, nil).Once()
+			}
+			if tt.wantErr == nil {
+				prStore.
+					On("AddReviewer", ctx, "pr-1", tt.userID, false, false).
+					Return(nil).Once()
+			}
+			if tt.wantStatus == domain.PRStatusOpen {
+				prStore.
+					On("UpdateStatus", ctx, "pr-1", domain.PRStatusOpen).
+					Return(nil).Once()
+			}
+
+			svc := NewService(teamStore, userStore, prStore, &mockTxManager{}, NewRandomSelector(), nil)
+
+			got, err := svc.AddReviewer(ctx, "pr-1", tt.userID)
+			if tt.wantErr != nil {
+				require.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Contains(t, got.AssignedReviewers, tt.userID)
+			assert.Equal(t, tt.wantStatus, got.Status)
+		})
+	}
+}
+
+func TestService_RemoveReviewer(t *testing.T) {
+	ctx := context.Background()
+
+	pr := domain.PullRequest{
+		ID:                 "pr-1",
+		AuthorID:           "author",
+		Status:             domain.PRStatusOpen,
+		AssignedReviewers:  []string{"u1", "u2"},
+		MandatoryReviewers: []string{"u1"},
+	}
+
+	t.Run("mandatory_without_force", func(t *testing.T) {
+		prStore := mocks.NewPullRequestStorage(t)
+		prStore.
+			On("GetPullRequestByIDForUpdate", ctx, "pr-1").
+			Return(pr, nil).Once()
+
+		svc := NewService(mocks.NewTeamStorage(t), mocks.NewUserStorage(t), prStore, &mockTxManager{}, NewRandomSelector(), nil)
+
+		_, err := svc.RemoveReviewer(ctx, "pr-1", "u1", false)
+		require.ErrorIs(t, err, domain.ErrMandatoryReviewer)
+	})
+
+	t.Run("removes", func(t *testing.T) {
+		teamStore := mocks.NewTeamStorage(t)
+		userStore := mocks.NewUserStorage(t)
+		prStore := mocks.NewPullRequestStorage(t)
+
+		prStore.
+			On("GetPullRequestByIDForUpdate", ctx, "pr-1").
+			Return(pr, nil).Once()
+
+		userStore.
+			On("GetUserByID", ctx, "author").
+			Return(&domain.User{ID: "author", TeamName: "team-A"}, nil).Once()
+
+		teamStore.
+			On("GetTeamSettings", ctx, "team-A").
+			Return(defaultSettings, nil).Once()
+
+		prStore.
+			On("RemoveReviewer", ctx, "pr-1", "u2").
+			Return(nil).Once()
+
+		svc := NewService(teamStore, userStore, prStore, &mockTxManager{}, NewRandomSelector(), nil)
+
+		got, err := svc.RemoveReviewer(ctx, "pr-1", "u2", false)
+		require.NoError(t, err)
+		assert.Equal(t, []string{"u1"}, got.AssignedReviewers)
+		assert.Equal(t, domain.PRStatusOpen, got.Status)
+	})
+}
+
+func TestService_ReassignReviewerTo(t *testing.T) {
+	ctx := context.Background()
+
+	teamStore := mocks.NewTeamStorage(t)
+	userStore := mocks.NewUserStorage(t)
+	prStore := mocks.NewPullRequestStorage(t)
+
+	settings := defaultSettings
+	settings.BackupTeams = []string{"team-B"}
+
+	prStore.
+		On("GetPullRequestByIDForUpdate", ctx, "pr-1").
+		Return(domain.PullRequest{ID: "pr-1", AuthorID: "author", Status: domain.PRStatusOpen, AssignedReviewers: []string{"u1", "u2"}}, nil).Once()
+
+	userStore.
+		On("GetUserByID", ctx, "author").
+		Return(&domain.User{ID: "author", TeamName: "team-A"}, nil).Once()
+
+	teamStore.
+		On("GetTeamSettings", ctx, "team-A").
+		Return(settings, nil).Once()
+
+	userStore.
+		On("GetUserByID", ctx, "b1").
+		Return(&domain.User{ID: "b1", TeamName: "team-B"}, nil).Once()
+
+	userStore.
+		On("ListActiveUserByTeam", ctx, "team-B").
+		Return([]domain.User{{ID: "b1", TeamName: "team-B"}}, nil).Once()
+
+	prStore.
+		On("ReplaceReviewer", ctx, "pr-1", "u1", "b1", true).
+		Return(nil).Once()
+
+	svc := NewService(teamStore, userStore, prStore, &mockTxManager{}, NewRandomSelector(), nil)
+
+	got, err := svc.ReassignReviewerTo(ctx, "pr-1", "u1", "b1", false)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"b1", "u2"}, got.AssignedReviewers)
+	assert.Equal(t, []string{"b1"}, got.FallbackReviewers)
+}
@@ -0,0 +1,574 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"slices"
+	"time"
+
+	"avito/internal/domain"
+)
+
+const (
+	// staffBatchSize how many waiting PRs are staffed after one merge.
+	staffBatchSize = 20
+	// backfillBatchSize how many understaffed PRs are handled by one BackfillReviewers call.
+	backfillBatchSize = 100
+)
+
+type pickResult struct {
+	reviewers  []string
+	fallback   []string // subset of reviewers taken from backup teams
+	mandatory  []string // subset of reviewers required by the team policy
+	atCapacity bool     // someone was skipped because of max_open_reviews
+}
+
+// pickForPullRequest chooses reviewers of the PR in addition to assigned ones in order: mandatory
+// reviewers of the team, an owner of changed files, reviewers covering required tags, reviewers
+// the seniority policy needs, the rest by the team strategy. Only new reviewers are returned.
+// Reviewers breaking the seniority policy together with assigned ones are an error, so is
+// an owner of changed files who is needed when there is no slot left for them.
+func (s *Service) pickForPullRequest(ctx context.Context, pool *candidatePool, settings domain.TeamSettings, authorID string, assigned, files, tags []string) (pickResult, error) {
+	mandatory, err := s.pickMandatory(ctx, pool, settings, authorID)
+	if err != nil {
+		return pickResult{}, err
+	}
+	mandatory = slices.DeleteFunc(mandatory, func(id string) bool { return slices.Contains(assigned, id) })
+	if free := max(settings.MaxReviewers-len(assigned), 0); len(mandatory) > free {
+		mandatory = mandatory[:free]
+	}
+	pool.choose(domain.ChosenMandatory, mandatory...)
+
+	preselected := slices.Clone(mandatory)
+	owner, err := s.pickCodeOwner(ctx, pool, settings, authorID, slices.Concat(assigned, mandatory), files)
+	if err != nil {
+		return pickResult{}, err
+	}
+	if owner != "" {
+		if len(assigned)+len(preselected) >= settings.MaxReviewers {
+			return pickResult{}, fmt.Errorf("%w: mandatory reviewers take all max_reviewers slots and none of them owns changed files", domain.ErrNoCandidate)
+		}
+		preselected = append(preselected, owner)
+		pool.choose(domain.ChosenCodeOwner, owner)
+	}
+	slots := settings.MaxReviewers - len(assigned) - len(preselected)
+
+	uncovered := slices.Clone(tags)
+	for _, matched := range matchedTags(pool, slices.Concat(assigned, preselected), tags) {
+		uncovered = slices.DeleteFunc(uncovered, func(tag string) bool {
+			return slices.Contains(matched, tag)
+		})
+	}
+	exclude := slices.Concat([]string{authorID}, assigned, preselected)
+	tagged, err := s.pickTagged(ctx, pool, settings, authorID, exclude, uncovered, slots)
+	if err != nil {
+		return pickResult{}, err
+	}
+	preselected = append(preselected, tagged...)
+	exclude = append(exclude, tagged...)
+	pool.choose(domain.ChosenTags, tagged...)
+	slots -= len(tagged)
+
+	var senior pickResult
+	if hasSeniorityPolicy(settings) {
+		reviewers, err := reviewerUsers(ctx, pool, slices.Concat(assigned, preselected))
+		if err != nil {
+			return pickResult{}, err
+		}
+		senior, err = s.pickForSeniority(ctx, pool, settings, authorID, reviewers, exclude, slots)
+		if err != nil {
+			return pickResult{}, err
+		}
+		preselected = append(preselected, senior.reviewers...)
+		exclude = append(exclude, senior.reviewers...)
+		pool.choose(domain.ChosenSeniority, senior.reviewers...)
+		slots -= len(senior.reviewers)
+	}
+
+	need := settings.MinReviewers - len(assigned) - len(preselected)
+	picked, err := s.pickReviewers(ctx, pool, settings, authorID, exclude, need, slots)
+	if err != nil {
+		return pickResult{}, err
+	}
+	pool.choose(domain.ChosenStrategy, picked.reviewers...)
+	picked.reviewers = append(preselected, picked.reviewers...)
+	picked.fallback = append(senior.fallback, picked.fallback...)
+	picked.mandatory = mandatory
+
+	if hasSeniorityPolicy(settings) {
+		reviewers, err := reviewerUsers(ctx, pool, slices.Concat(assigned, picked.reviewers))
+		if err != nil {
+			return pickResult{}, err
+		}
+		if err := checkSeniority(settings, reviewers); err != nil {
+			return pickResult{}, err
+		}
+	}
+	return picked, nil
+}
+
+// pickReviewers selects up to quantity reviewers from the team of settings,
+// backup teams are asked in declared order while there are less than need reviewers.
+func (s *Service) pickReviewers(ctx context.Context, pool *candidatePool, settings domain.TeamSettings, authorID string, exclude []string, need, quantity int) (pickResult, error) {
+	var res pickResult
+	if quantity <= 0 {
+		return res, nil
+	}
+
+	candidates, atCapacity, err := s.availableCandidates(ctx, pool, settings.TeamName, exclude)
+	if err != nil {
+		return res, err
+	}
+	res.atCapacity = atCapacity
+
+	prefer := preferenceOf(settings, time.Now())
+
+	res.reviewers, err = s.selectPreferred(ctx, pool, SelectInput{
+		TeamName:   settings.TeamName,
+		Strategy:   settings.Strategy,
+		AuthorID:   authorID,
+		Candidates: candidates,
+		Quantity:   quantity,
+		Counter:    pool,
+	}, prefer)
+	if err != nil {
+		return res, err
+	}
+
+	for _, teamName := range settings.BackupTeams {
+		if len(res.reviewers) >= need {
+			break
+		}
+
+		candidates, atCapacity, err := s.availableCandidates(ctx, pool, teamName, slices.Concat(exclude, res.reviewers))
+		if err != nil {
+			return res, err
+		}
+		res.atCapacity = res.atCapacity || atCapacity
+
+		chosen, err := s.selectPreferred(ctx, pool, SelectInput{
+			TeamName:   teamName,
+			Strategy:   settings.Strategy,
+			AuthorID:   authorID,
+			Candidates: candidates,
+			Quantity:   need - len(res.reviewers),
+			Counter:    pool,
+		}, prefer)
+		if err != nil {
+			return res, err
+		}
+		res.reviewers = append(res.reviewers, chosen...)
+		res.fallback = append(res.fallback, chosen...)
+	}
+
+	return res, nil
+}
+
+// pickMandatory returns mandatory reviewers of the team who can review now:
+// active, not absent and not the author. max_open_reviews is not checked for them.
+func (s *Service) pickMandatory(ctx context.Context, pool *candidatePool, settings domain.TeamSettings, authorID string) ([]string, error) {
+	out := make([]string, 0, len(settings.MandatoryReviewers))
+	for _, userID := range settings.MandatoryReviewers {
+		if userID == authorID {
+			continue
+		}
+
+		user, err := s.userStore.GetUserByID(ctx, userID)
+		if errors.Is(err, domain.ErrNotFound) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		members, err := pool.activeMembers(ctx, user.TeamName)
+		if err != nil {
+			return nil, err
+		}
+		if slices.ContainsFunc(members, func(member domain.User) bool { return member.ID == userID }) {
+			out = append(out, userID)
+		}
+	}
+	return out, nil
+}
+
+// preferenceOf returns which candidates the team prefers at now, nil means no preference.
+func preferenceOf(settings domain.TeamSettings, now time.Time) func(domain.User) bool {
+	if !settings.PreferWorkingHours {
+		return nil
+	}
+	return func(user domain.User) bool {
+		return user.IsWorkingAt(now)
+	}
+}
+
+// selectPreferred selects among preferred candidates first, the rest of candidates
+// are asked only when preferred ones are not enough.
+func (s *Service) selectPreferred(ctx context.Context, pool *candidatePool, in SelectInput, prefer func(domain.User) bool) ([]string, error) {
+	if prefer == nil {
+		return s.selectRecorded(ctx, pool, in)
+	}
+
+	preferred := make([]domain.User, 0, len(in.Candidates))
+	rest := make([]domain.User, 0)
+	for _, user := range in.Candidates {
+		if prefer(user) {
+			preferred = append(preferred, user)
+		} else {
+			rest = append(rest, user)
+		}
+	}
+
+	quantity := in.Quantity
+	in.Candidates = preferred
+	chosen, err := s.selectRecorded(ctx, pool, in)
+	if err != nil {
+		return nil, err
+	}
+	if len(chosen) >= quantity || len(rest) == 0 {
+		return chosen, nil
+	}
+
+	in.Candidates = rest
+	in.Quantity = quantity - len(chosen)
+	more, err := s.selectRecorded(ctx, pool, in)
+	if err != nil {
+		return nil, err
+	}
+	return append(chosen, more...), nil
+}
+
+// selectRecorded runs the selector with the random source of the pool and records the step
+// with the resolved strategy, so replay does not depend on the default strategy of the service.
+// Selections without candidates are not recorded as they do not use randomness.
+func (s *Service) selectRecorded(ctx context.Context, pool *candidatePool, in SelectInput) ([]string, error) {
+	if len(in.Candidates) == 0 {
+		return nil, nil
+	}
+
+	in.Strategy = s.resolveStrategy(in.Strategy)
+	in.Inputs = &domain.SelectionInputs{}
+	in.History = pool
+	in.Rand = pool.rng
+	in.DryRun = pool.dryRun
+	chosen, err := s.selector.Select(ctx, in)
+	if err != nil {
+		return nil, err
+	}
+	pool.record(in, chosen)
+	return chosen, nil
+}
+
+// availableCandidates returns active members of the team except excluded ones
+// and the ones who reached their max_open_reviews.
+func (s *Service) availableCandidates(ctx context.Context, pool *candidatePool, teamName string, exclude []string) ([]domain.User, bool, error) {
+	candidates, err := pool.activeMembers(ctx, teamName)
+	if err != nil {
+		return nil, false, err
+	}
+	candidates = filterUsersExclude(candidates, exclude)
+
+	limited := make([]string, 0)
+	for _, user := range candidates {
+		if user.MaxOpenReviews != nil {
+			limited = append(limited, user.ID)
+		}
+	}
+	if len(limited) == 0 {
+		return candidates, false, nil
+	}
+
+	load, err := pool.CountOpenReviews(ctx, limited)
+	if err != nil {
+		return nil, false, err
+	}
+
+	atCapacity := false
+	out := make([]domain.User, 0, len(candidates))
+	for _, user := range candidates {
+		if user.MaxOpenReviews != nil && load[user.ID] >= *user.MaxOpenReviews {
+			atCapacity = true
+			continue
+		}
+		out = append(out, user)
+	}
+	return out, atCapacity, nil
+}
+
+// planReplacement chooses who replaces oldUser in the PR: a candidate from the team
+// of oldUser or its backup teams, users from exclude are never chosen.
+// When the rest of reviewers do not keep the seniority policy of the team, only
+// candidates fitting it are asked and ErrSeniorityPolicy is returned if there is none.
+// pr and pool are updated in memory only, returned NewReviewerID is empty
+// when there was no candidate.
+func (s *Service) planReplacement(ctx context.Context, pool *candidatePool, pr *domain.PullRequest, oldUser domain.User, exclude []string) (domain.Reassignment, error) {
+	reassignment := domain.Reassignment{
+		PullRequestID: pr.ID,
+		OldReviewerID: oldUser.ID,
+	}
+
+	settings, err := pool.teamSettings(ctx, oldUser.TeamName)
+	if err != nil {
+		return reassignment, err
+	}
+
+	exclude = slices.Concat([]string{oldUser.ID, pr.AuthorID}, pr.AssignedReviewers, exclude)
+
+	var (
+		newID      string
+		isFallback bool
+	)
+	need, ok := seniorityConstraint{}, false
+	if hasSeniorityPolicy(settings) {
+		rest, err := reviewerUsers(ctx, pool, slices.DeleteFunc(slices.Clone(pr.AssignedReviewers), func(id string) bool {
+			return id == oldUser.ID
+		}))
+		if err != nil {
+			return reassignment, err
+		}
+		need, ok = seniorityNeed(settings, rest)
+	}
+	if ok {
+		user, fallback, err := s.pickFitting(ctx, pool, settings, pr.AuthorID, exclude, need.fits)
+		if err != nil {
+			return reassignment, err
+		}
+		if user == nil {
+			return reassignment, fmt.Errorf("%w: %s", domain.ErrSeniorityPolicy, need.name)
+		}
+		newID, isFallback = user.ID, fallback
+		pool.choose(domain.ChosenSeniority, newID)
+	} else {
+		picked, err := s.pickReviewers(ctx, pool, settings, pr.AuthorID, exclude, 1, 1)
+		if err != nil {
+			return reassignment, err
+		}
+		if len(picked.reviewers) == 0 {
+			return reassignment, nil
+		}
+		newID, isFallback = picked.reviewers[0], len(picked.fallback) > 0
+		pool.choose(domain.ChosenStrategy, newID)
+	}
+
+	for i, id := range pr.AssignedReviewers {
+		if id == oldUser.ID {
+			pr.AssignedReviewers[i] = newID
+			break
+		}
+	}
+	pr.FallbackReviewers = slices.DeleteFunc(pr.FallbackReviewers, func(id string) bool {
+		return id == oldUser.ID
+	})
+	pr.MandatoryReviewers = slices.DeleteFunc(pr.MandatoryReviewers, func(id string) bool {
+		return id == oldUser.ID
+	})
+	if isFallback {
+		pr.FallbackReviewers = append(pr.FallbackReviewers, newID)
+	}
+	pool.moveReview(oldUser.ID, newID)
+
+	reassignment.NewReviewerID = newID
+	reassignment.IsFallback = isFallback
+	return reassignment, nil
+}
+
+// reassignOpenReviews replaces users in all their open reviews, none of users is chosen
+// as a replacement. Replacements are written to storage with one batch.
+func (s *Service) reassignOpenReviews(ctx context.Context, users []domain.User) ([]domain.Reassignment, error) {
+	ids := userIDs(users)
+
+	prs, err := s.prStore.ListOpenByReviewersForUpdate(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+
+	pool := s.newCandidatePool()
+	pool.batch(prs)
+	out := make([]domain.Reassignment, 0)
+	replaced := make([]domain.Reassignment, 0)
+	audits := make([]domain.AssignmentAudit, 0)
+	now := time.Now().UTC()
+	for _, pr := range prs {
+		pool.begin(pr.ID)
+		for _, user := range users {
+			if !slices.Contains(pr.AssignedReviewers, user.ID) {
+				continue
+			}
+
+			reassignment, err := s.planReplacement(ctx, pool, &pr, user, ids)
+			if err != nil && !errors.Is(err, domain.ErrSeniorityPolicy) {
+				return nil, err
+			}
+			out = append(out, reassignment)
+			if reassignment.NewReviewerID != "" {
+				replaced = append(replaced, reassignment)
+			}
+		}
+		if len(pool.steps) > 0 {
+			audits = append(audits, pool.audit(pr.ID, domain.AssignmentReassign, now))
+		}
+	}
+
+	if len(replaced) > 0 {
+		if err := s.prStore.ReplaceReviewers(ctx, replaced); err != nil {
+			return nil, err
+		}
+		if err := s.prStore.AddAssignmentAudits(ctx, audits); err != nil {
+			return nil, err
+		}
+	}
+	return out, nil
+}
+
+// topUpReviewers assigns missing reviewers to the open PR up to MaxReviewers of the author's team
+// like CreatePullRequest does, changed files and required tags are not known any more.
+// NEEDS_REVIEWERS PR becomes OPEN when it gets at least MinReviewers reviewers.
+func (s *Service) topUpReviewers(ctx context.Context, prID string) (domain.PullRequest, []string, error) {
+	pr, err := s.prStore.GetPullRequestByIDForUpdate(ctx, prID)
+	if err != nil {
+		return pr, nil, err
+	}
+	if !pr.Status.IsOpen() {
+		return pr, nil, nil
+	}
+
+	author, err := s.userStore.GetUserByID(ctx, pr.AuthorID)
+	if err != nil {
+		return pr, nil, err
+	}
+
+	settings, err := s.teamStore.GetTeamSettings(ctx, author.TeamName)
+	if err != nil {
+		return pr, nil, err
+	}
+
+	pool := s.newCandidatePool()
+	pool.begin(prID)
+	picked, err := s.pickForPullRequest(ctx, pool, settings, pr.AuthorID, pr.AssignedReviewers, nil, nil)
+	if err != nil {
+		return pr, nil, err
+	}
+
+	for _, id := range picked.reviewers {
+		isFallback := slices.Contains(picked.fallback, id)
+		isMandatory := slices.Contains(picked.mandatory, id)
+		if err := s.prStore.AddReviewer(ctx, prID, id, isFallback, isMandatory); err != nil {
+			return pr, nil, err
+		}
+		pr.AssignedReviewers = append(pr.AssignedReviewers, id)
+		if isFallback {
+			pr.FallbackReviewers = append(pr.FallbackReviewers, id)
+		}
+		if isMandatory {
+			pr.MandatoryReviewers = append(pr.MandatoryReviewers, id)
+		}
+	}
+	if len(picked.reviewers) > 0 {
+		audit := pool.audit(prID, domain.AssignmentBackfill, time.Now().UTC())
+		if err := s.prStore.AddAssignmentAudits(ctx, []domain.AssignmentAudit{audit}); err != nil {
+			return pr, nil, err
+		}
+	}
+
+	if pr.Status == domain.PRStatusNeedsReviewers && len(pr.AssignedReviewers) >= settings.MinReviewers {
+		if err := s.prStore.UpdateStatus(ctx, prID, domain.PRStatusOpen); err != nil {
+			return pr, nil, err
+		}
+		pr.Status = domain.PRStatusOpen
+	}
+
+	return pr, picked.reviewers, nil
+}
+
+// staffWaitingPullRequests tops up the least recently tried NEEDS_REVIEWERS pull requests,
+// every PR in its own transaction. They are marked as attempted like backfill batches, so
+// PRs which can't be staffed don't hold back the rest. It is called after the change which
+// freed reviewers is committed and is best-effort: failures are logged, the backfill job
+// retries such PRs later.
+func (s *Service) staffWaitingPullRequests(ctx context.Context) {
+	prs, err := s.prStore.ListByStatus(ctx, domain.PRStatusNeedsReviewers, staffBatchSize)
+	if err != nil {
+		log.Printf("staffing waiting pull requests failed: %v", err)
+		return
+	}
+	if len(prs) == 0 {
+		return
+	}
+	if err := s.prStore.MarkBackfillAttempted(ctx, pullRequestIDs(prs)); err != nil {
+		log.Printf("staffing waiting pull requests failed: %v", err)
+		return
+	}
+
+	for _, pr := range prs {
+		err := s.tx.WithTx(ctx, func(ctx context.Context) error {
+			_, _, err := s.topUpReviewers(ctx, pr.ID)
+			return err
+		})
+		if err != nil {
+			log.Printf("staffing pull request %q failed: %v", pr.ID, err)
+		}
+	}
+}
+
+// BackfillReviewers tops up reviewers of the PR, or of the least recently backfilled
+// understaffed open PRs when prID is empty. Batch PRs are marked as attempted before
+// they are handled, so PRs which can't be staffed don't hold back the rest.
+// Every PR is handled in its own transaction,
+// only PRs which got new reviewers or failed are reported in the batch mode.
+// A failed PR is reported with Err and does not stop the batch.
+func (s *Service) BackfillReviewers(ctx context.Context, prID string) ([]domain.Backfill, error) {
+	if prID != "" {
+		var result domain.Backfill
+		err := s.tx.WithTx(ctx, func(ctx context.Context) error {
+			pr, added, err := s.topUpReviewers(ctx, prID)
+			if err != nil {
+				return err
+			}
+			if err := requireOpen(pr); err != nil {
+				return err
+			}
+			result = domain.Backfill{PullRequest: pr, AddedReviewers: added}
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+		return []domain.Backfill{result}, nil
+	}
+
+	prs, err := s.prStore.ListUnderstaffed(ctx, backfillBatchSize)
+	if err != nil {
+		return nil, err
+	}
+	if err := s.prStore.MarkBackfillAttempted(ctx, pullRequestIDs(prs)); err != nil {
+		return nil, err
+	}
+
+	out := make([]domain.Backfill, 0)
+	for _, pr := range prs {
+		err := s.tx.WithTx(ctx, func(ctx context.Context) error {
+			pr, added, err := s.topUpReviewers(ctx, pr.ID)
+			if err != nil {
+				return err
+			}
+			if len(added) > 0 {
+				out = append(out, domain.Backfill{PullRequest: pr, AddedReviewers: added})
+			}
+			return nil
+		})
+		if err != nil {
+			out = append(out, domain.Backfill{PullRequest: pr, Err: err})
+		}
+	}
+	return out, nil
+}
+
+func pullRequestIDs(prs []domain.PullRequest) []string {
+	ids := make([]string, 0, len(prs))
+	for _, pr := range prs {
+		ids = append(ids, pr.ID)
+	}
+	return ids
+}
@@ -0,0 +1,365 @@
+package service
+
+import (
+	"context"
+	"math/rand/v2"
+	"slices"
+	"sort"
+	"sync"
+
+	"avito/internal/domain"
+)
+
+// ReviewerSelector picks reviewers among already filtered candidates.
+// Candidates never contain the author or users that must be excluded.
+type ReviewerSelector interface {
+	Select(ctx context.Context, in SelectInput) ([]string, error)
+}
+
+// SelectInput Counter knows open reviews including the ones planned by the current
+// operation but not written yet; nil means selector counts them in storage itself.
+// Rand is the only random source selector may use, nil means the global one.
+// DryRun selection is not used, selector must not remember it.
+// History like Counter lists recent reviewers for the current operation,
+// nil means selector lists them in storage itself.
+// Inputs not nil receives outside state the selector used (loads, history, weights, rotation),
+// on Replay the selector takes that state from Inputs instead.
+type SelectInput struct {
+	TeamName   string
+	Strategy   domain.SelectionStrategy
+	AuthorID   string
+	Candidates []domain.User
+	Quantity   int
+	Counter    openReviewsCounter
+	History    recentReviewersLister
+	Rand       *rand.Rand
+	DryRun     bool
+	Inputs     *domain.SelectionInputs
+	Replay     bool
+}
+
+func (in SelectInput) rng() *rand.Rand {
+	if in.Rand != nil {
+		return in.Rand
+	}
+	return rand.New(globalSource{})
+}
+
+// recorded returns inputs to replay with, nil means selector uses the current state.
+func (in SelectInput) recorded() *domain.SelectionInputs {
+	if in.Replay {
+		return in.Inputs
+	}
+	return nil
+}
+
+// record saves the state the selector used unless it is replaying.
+func (in SelectInput) record(fn func(inputs *domain.SelectionInputs)) {
+	if in.Inputs != nil && !in.Replay {
+		fn(in.Inputs)
+	}
+}
+
+type globalSource struct{}
+
+func (globalSource) Uint64() uint64 {
+	return rand.Uint64()
+}
+
+// RandomSelector
+
+type RandomSelector struct{}
+
+func NewRandomSelector() *RandomSelector {
+	return &RandomSelector{}
+}
+
+func (s *RandomSelector) Select(_ context.Context, in SelectInput) ([]string, error) {
+	return chooseReviewers(in.rng(), in.Candidates, in.Quantity), nil
+}
+
+// RoundRobinSelector remembers the last assigned user per team (in memory)
+// and continues from the next one ordered by user id.
+
+type RoundRobinSelector struct {
+	mu   sync.Mutex
+	last map[string]string
+}
+
+func NewRoundRobinSelector() *RoundRobinSelector {
+	return &RoundRobinSelector{
+		last: make(map[string]string),
+	}
+}
+
+func (s *RoundRobinSelector) Select(_ context.Context, in SelectInput) ([]string, error) {
+	if len(in.Candidates) == 0 || in.Quantity <= 0 {
+		return nil, nil
+	}
+
+	ids := userIDs(in.Candidates)
+	slices.Sort(ids)
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	last := s.last[in.TeamName]
+	if recorded := in.recorded(); recorded != nil {
+		last = recorded.Last
+	}
+	in.record(func(inputs *domain.SelectionInputs) { inputs.Last = last })
+
+	start, _ := slices.BinarySearch(ids, last)
+	if start < len(ids) && ids[start] == last {
+		start++
+	}
+
+	quantity := min(in.Quantity, len(ids))
+	out := make([]string, 0, quantity)
+	for i := 0; i < quantity; i++ {
+		out = append(out, ids[(start+i)%len(ids)])
+	}
+
+	if !in.DryRun {
+		s.last[in.TeamName] = out[len(out)-1]
+	}
+	return out, nil
+}
+
+// LeastLoadedSelector prefers candidates with the smallest number of OPEN reviews,
+// ties are broken randomly.
+
+type openReviewsCounter interface {
+	CountOpenReviews(ctx context.Context, userIDs []string) (map[string]int, error)
+}
+
+type LeastLoadedSelector struct {
+	counter openReviewsCounter
+}
+
+func NewLeastLoadedSelector(counter openReviewsCounter) *LeastLoadedSelector {
+	return &LeastLoadedSelector{counter: counter}
+}
+
+func (s *LeastLoadedSelector) Select(ctx context.Context, in SelectInput) ([]string, error) {
+	if len(in.Candidates) == 0 || in.Quantity <= 0 {
+		return nil, nil
+	}
+
+	counter := s.counter
+	if in.Counter != nil {
+		counter = in.Counter
+	}
+
+	ids := userIDs(in.Candidates)
+	var load map[string]int
+	if recorded := in.recorded(); recorded != nil {
+		load = recorded.Loads
+	} else {
+		var err error
+		load, err = counter.CountOpenReviews(ctx, ids)
+		if err != nil {
+			return nil, err
+		}
+	}
+	in.record(func(inputs *domain.SelectionInputs) { inputs.Loads = load })
+
+	in.rng().Shuffle(len(ids), func(i, j int) {
+		ids[i], ids[j] = ids[j], ids[i]
+	})
+	sort.SliceStable(ids, func(i, j int) bool {
+		return load[ids[i]] < load[ids[j]]
+	})
+
+	return ids[:min(in.Quantity, len(ids))], nil
+}
+
+// WeightedSelector picks candidates randomly proportionally to their weight.
+// Users without configured weight get defaultWeight.
+
+type WeightedSelector struct {
+	weights       map[string]int
+	defaultWeight int
+}
+
+func NewWeightedSelector(weights map[string]int, defaultWeight int) *WeightedSelector {
+	if defaultWeight <= 0 {
+		defaultWeight = 1
+	}
+	return &WeightedSelector{
+		weights:       weights,
+		defaultWeight: defaultWeight,
+	}
+}
+
+func (s *WeightedSelector) Select(_ context.Context, in SelectInput) ([]string, error) {
+	if len(in.Candidates) == 0 || in.Quantity <= 0 {
+		return nil, nil
+	}
+
+	configured := make(map[string]int, len(in.Candidates))
+	for _, user := range in.Candidates {
+		weight, ok := s.weights[user.ID]
+		if !ok {
+			weight = s.defaultWeight
+		}
+		configured[user.ID] = weight
+	}
+	if recorded := in.recorded(); recorded != nil {
+		configured = recorded.Weights
+	}
+	in.record(func(inputs *domain.SelectionInputs) { inputs.Weights = configured })
+
+	pool := make([]string, 0, len(in.Candidates))
+	weights := make([]int, 0, len(in.Candidates))
+	total := 0
+	for _, user := range in.Candidates {
+		weight := configured[user.ID]
+		if weight <= 0 {
+			continue
+		}
+		pool = append(pool, user.ID)
+		weights = append(weights, weight)
+		total += weight
+	}
+
+	rng := in.rng()
+	quantity := min(in.Quantity, len(pool))
+	out := make([]string, 0, quantity)
+	for len(out) < quantity {
+		r := rng.IntN(total)
+		for i, weight := range weights {
+			if r < weight {
+				out = append(out, pool[i])
+				total -= weight
+				pool = slices.Delete(pool, i, i+1)
+				weights = slices.Delete(weights, i, i+1)
+				break
+			}
+			r -= weight
+		}
+	}
+
+	return out, nil
+}
+
+// PairDiversitySelector picks candidates randomly, a candidate who appears k times
+// among the author's last window assignments gets weight 1/(1+k),
+// so reviews spread across the team instead of the same pairs.
+
+type recentReviewersLister interface {
+	RecentReviewers(ctx context.Context, authorID string, limit int) ([]string, error)
+}
+
+type PairDiversitySelector struct {
+	history recentReviewersLister
+	window  int
+}
+
+func NewPairDiversitySelector(history recentReviewersLister, window int) *PairDiversitySelector {
+	if window <= 0 {
+		window = 20
+	}
+	return &PairDiversitySelector{
+		history: history,
+		window:  window,
+	}
+}
+
+func (s *PairDiversitySelector) Select(ctx context.Context, in SelectInput) ([]string, error) {
+	if len(in.Candidates) == 0 || in.Quantity <= 0 {
+		return nil, nil
+	}
+
+	pool := userIDs(in.Candidates)
+
+	var paired map[string]int
+	if recorded := in.recorded(); recorded != nil {
+		paired = recorded.Pairs
+	} else {
+		history := s.history
+		if in.History != nil {
+			history = in.History
+		}
+		recent, err := history.RecentReviewers(ctx, in.AuthorID, s.window)
+		if err != nil {
+			return nil, err
+		}
+		paired = make(map[string]int, len(pool))
+		for _, id := range recent {
+			if slices.Contains(pool, id) {
+				paired[id]++
+			}
+		}
+	}
+	in.record(func(inputs *domain.SelectionInputs) { inputs.Pairs = paired })
+
+	weights := make([]float64, 0, len(pool))
+	total := 0.0
+	for _, id := range pool {
+		weight := 1 / float64(1+paired[id])
+		weights = append(weights, weight)
+		total += weight
+	}
+
+	rng := in.rng()
+	quantity := min(in.Quantity, len(pool))
+	out := make([]string, 0, quantity)
+	for len(out) < quantity {
+		r := rng.Float64() * total
+		i := 0
+		for ; i < len(weights)-1 && r >= weights[i]; i++ {
+			r -= weights[i]
+		}
+		out = append(out, pool[i])
+		total -= weights[i]
+		pool = slices.Delete(pool, i, i+1)
+		weights = slices.Delete(weights, i, i+1)
+	}
+
+	return out, nil
+}
+
+// StrategySelector routes selection to the selector of the strategy
+// configured for the team, def is used when strategy is empty or unknown.
+
+type StrategySelector struct {
+	def        ReviewerSelector
+	byStrategy map[domain.SelectionStrategy]ReviewerSelector
+}
+
+func NewStrategySelector(def ReviewerSelector, byStrategy map[domain.SelectionStrategy]ReviewerSelector) *StrategySelector {
+	return &StrategySelector{
+		def:        def,
+		byStrategy: byStrategy,
+	}
+}
+
+func (s *StrategySelector) Select(ctx context.Context, in SelectInput) ([]string, error) {
+	if selector, ok := s.byStrategy[in.Strategy]; ok {
+		return selector.Select(ctx, in)
+	}
+	return s.def.Select(ctx, in)
+}
+
+// Resolve returns the strategy Select uses for strategy, empty when
+// the default selector is not one of byStrategy.
+func (s *StrategySelector) Resolve(strategy domain.SelectionStrategy) domain.SelectionStrategy {
+	if _, ok := s.byStrategy[strategy]; ok {
+		return strategy
+	}
+	for name, selector := range s.byStrategy {
+		if selector == s.def {
+			return name
+		}
+	}
+	return ""
+}
+
+func userIDs(users []domain.User) []string {
+	ids := make([]string, 0, len(users))
+	for _, user := range users {
+		ids = append(ids, user.ID)
+	}
+	return ids
+}
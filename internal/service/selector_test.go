@@ -0,0 +1,180 @@
+package service
+
+import (
+	"avito/internal/domain"
+	"avito/internal/service/mocks"
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRoundRobinSelector_RotatesWithinTeam(t *testing.T) {
+	ctx := context.Background()
+	selector := NewRoundRobinSelector()
+
+	candidates := []domain.User{{ID: "u3"}, {ID: "u1"}, {ID: "u2"}}
+
+	got1, err := selector.Select(ctx, SelectInput{TeamName: "team-A", Candidates: candidates, Quantity: 2})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"u1", "u2"}, got1)
+
+	got2, err := selector.Select(ctx, SelectInput{TeamName: "team-A", Candidates: candidates, Quantity: 2})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"u3", "u1"}, got2)
+
+	got3, err := selector.Select(ctx, SelectInput{TeamName: "team-B", Candidates: candidates, Quantity: 1})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"u1"}, got3)
+}
+
+func TestLeastLoadedSelector_PrefersFreeReviewers(t *testing.T) {
+	ctx := context.Background()
+
+	prStore := mocks.NewPullRequestStorage(t)
+	prStore.
+		On("CountOpenReviews", ctx, mock.Anything).
+		Return(map[string]int{"u1": 2, "u3": 1}, nil).Once()
+
+	selector := NewLeastLoadedSelector(prStore)
+
+	got, err := selector.Select(ctx, SelectInput{
+		Candidates: []domain.User{{ID: "u1"}, {ID: "u2"}, {ID: "u3"}},
+		Quantity:   2,
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"u2", "u3"}, got)
+}
+
+func TestLeastLoadedSelector_BreaksTiesRandomly(t *testing.T) {
+	ctx := context.Background()
+
+	prStore := mocks.NewPullRequestStorage(t)
+	prStore.
+		On("CountOpenReviews", ctx, mock.Anything).
+		Return(map[string]int{"u1": 3}, nil)
+
+	selector := NewLeastLoadedSelector(prStore)
+
+	seen := make(map[string]int)
+	for i := 0; i < 100; i++ {
+		got, err := selector.Select(ctx, SelectInput{
+			Candidates: []domain.User{{ID: "u1"}, {ID: "u2"}, {ID: "u3"}},
+			Quantity:   1,
+		})
+		require.NoError(t, err)
+		require.Len(t, got, 1)
+		seen[got[0]]++
+	}
+
+	assert.Zero(t, seen["u1"])
+	assert.Positive(t, seen["u2"])
+	assert.Positive(t, seen["u3"])
+}
+
+func TestWeightedSelector_SkipsZeroWeight(t *testing.T) {
+	ctx := context.Background()
+	selector := NewWeightedSelector(map[string]int{"u1": 0, "u2": 5}, 1)
+
+	for i := 0; i < 20; i++ {
+		got, err := selector.Select(ctx, SelectInput{
+			Candidates: []domain.User{{ID: "u1"}, {ID: "u2"}, {ID: "u3"}},
+			Quantity:   2,
+		})
+		require.NoError(t, err)
+		assert.ElementsMatch(t, []string{"u2", "u3"}, got)
+	}
+}
+
+func TestStrategySelector_RoutesByStrategy(t *testing.T) {
+	ctx := context.Background()
+
+	selector := NewStrategySelector(NewWeightedSelector(map[string]int{"u1": 0}, 1), map[domain.SelectionStrategy]ReviewerSelector{
+		domain.StrategyRoundRobin: NewRoundRobinSelector(),
+	})
+
+	candidates := []domain.User{{ID: "u1"}, {ID: "u2"}}
+
+	got, err := selector.Select(ctx, SelectInput{Strategy: domain.StrategyRoundRobin, Candidates: candidates, Quantity: 1})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"u1"}, got)
+
+	got, err = selector.Select(ctx, SelectInput{Candidates: candidates, Quantity: 1})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"u2"}, got)
+}
+
+func TestPairDiversitySelector_DownWeightsRecentPairs(t *testing.T) {
+	ctx := context.Background()
+
+	prStore := mocks.NewPullRequestStorage(t)
+	prStore.
+		On("RecentReviewers", ctx, "author", 10).
+		Return([]string{"u1", "u1", "u1", "u1", "u1", "u1", "u1", "u1", "u1", "u2"}, nil)
+
+	selector := NewPairDiversitySelector(prStore, 10)
+
+	seen := make(map[string]int)
+	for i := 0; i < 1000; i++ {
+		got, err := selector.Select(ctx, SelectInput{
+			AuthorID:   "author",
+			Candidates: []domain.User{{ID: "u1"}, {ID: "u2"}, {ID: "u3"}},
+			Quantity:   1,
+		})
+		require.NoError(t, err)
+		require.Len(t, got, 1)
+		seen[got[0]]++
+	}
+
+	assert.Greater(t, seen["u3"], seen["u2"])
+	assert.Greater(t, seen["u2"], seen["u1"])
+	assert.Positive(t, seen["u1"])
+}
+
+func TestLeastLoadedSelector_RecordsAndReplaysLoads(t *testing.T) {
+	ctx := context.Background()
+	candidates := []domain.User{{ID: "u1"}, {ID: "u2"}, {ID: "u3"}}
+
+	prStore := mocks.NewPullRequestStorage(t)
+	prStore.
+		On("CountOpenReviews", ctx, mock.Anything).
+		Return(map[string]int{"u1": 2, "u3": 1}, nil).Once()
+
+	selector := NewLeastLoadedSelector(prStore)
+
+	inputs := &domain.SelectionInputs{}
+	got, err := selector.Select(ctx, SelectInput{Candidates: candidates, Quantity: 1, Inputs: inputs})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"u2"}, got)
+	assert.Equal(t, map[string]int{"u1": 2, "u3": 1}, inputs.Loads)
+
+	// loads changed since, replay does not ask storage
+	replayed, err := selector.Select(ctx, SelectInput{Candidates: candidates, Quantity: 1, Inputs: inputs, Replay: true, DryRun: true})
+	require.NoError(t, err)
+	assert.Equal(t, got, replayed)
+}
+
+func TestRoundRobinSelector_ReplaysRecordedRotation(t *testing.T) {
+	ctx := context.Background()
+	selector := NewRoundRobinSelector()
+	candidates := []domain.User{{ID: "u1"}, {ID: "u2"}, {ID: "u3"}}
+
+	_, err := selector.Select(ctx, SelectInput{TeamName: "team-A", Candidates: candidates, Quantity: 1})
+	require.NoError(t, err)
+
+	inputs := &domain.SelectionInputs{}
+	got, err := selector.Select(ctx, SelectInput{TeamName: "team-A", Candidates: candidates, Quantity: 1, Inputs: inputs})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"u2"}, got)
+	assert.Equal(t, "u1", inputs.Last)
+
+	_, err = selector.Select(ctx, SelectInput{TeamName: "team-A", Candidates: candidates, Quantity: 1})
+	require.NoError(t, err)
+
+	replayed, err := selector.Select(ctx, SelectInput{TeamName: "team-A", Candidates: candidates, Quantity: 1, Inputs: inputs, Replay: true, DryRun: true})
+	require.NoError(t, err)
+	assert.Equal(t, got, replayed)
+	assert.Equal(t, "u3", selector.last["team-A"])
+}
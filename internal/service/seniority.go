@@ -0,0 +1,174 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"avito/internal/domain"
+)
+
+// seniorityConstraint a rule of the team seniority policy the reviewers do not meet yet.
+type seniorityConstraint struct {
+	name string                 // used in ErrSeniorityPolicy
+	fits func(domain.User) bool // whether the user helps to meet the rule
+}
+
+var (
+	requireSeniorConstraint = seniorityConstraint{
+		name: "at least one senior or lead reviewer is required",
+		fits: func(user domain.User) bool { return user.Seniority.AtLeastSenior() },
+	}
+	juniorsNotAloneConstraint = seniorityConstraint{
+		name: "juniors must not review alone",
+		fits: func(user domain.User) bool { return user.Seniority != domain.SeniorityJunior },
+	}
+)
+
+func hasSeniorityPolicy(settings domain.TeamSettings) bool {
+	return settings.RequireSenior || settings.JuniorsNotAlone
+}
+
+// seniorityNeed returns the rule the next reviewer must fit so that any reviewers chosen
+// after them keep the policy, ok is false when reviewers already guarantee it.
+// A senior fits both rules, so RequireSenior is asked first.
+func seniorityNeed(settings domain.TeamSettings, reviewers []domain.User) (seniorityConstraint, bool) {
+	if settings.RequireSenior && !slices.ContainsFunc(reviewers, requireSeniorConstraint.fits) {
+		return requireSeniorConstraint, true
+	}
+	if settings.JuniorsNotAlone && !slices.ContainsFunc(reviewers, juniorsNotAloneConstraint.fits) {
+		return juniorsNotAloneConstraint, true
+	}
+	return seniorityConstraint{}, false
+}
+
+// checkSeniority returns ErrSeniorityPolicy naming the first rule reviewers break.
+func checkSeniority(settings domain.TeamSettings, reviewers []domain.User) error {
+	if settings.RequireSenior && !slices.ContainsFunc(reviewers, requireSeniorConstraint.fits) {
+		return fmt.Errorf("%w: %s", domain.ErrSeniorityPolicy, requireSeniorConstraint.name)
+	}
+	hasJunior := slices.ContainsFunc(reviewers, func(user domain.User) bool { return user.Seniority == domain.SeniorityJunior })
+	if settings.JuniorsNotAlone && hasJunior && !slices.ContainsFunc(reviewers, juniorsNotAloneConstraint.fits) {
+		return fmt.Errorf("%w: %s", domain.ErrSeniorityPolicy, juniorsNotAloneConstraint.name)
+	}
+	return nil
+}
+
+// pickForSeniority picks up to quantity reviewers the policy needs in addition to assigned ones
+// from the team of settings and then its backup teams. Picked reviewers from backup teams
+// are returned in fallback too.
+func (s *Service) pickForSeniority(ctx context.Context, pool *candidatePool, settings domain.TeamSettings, authorID string, assigned []domain.User, exclude []string, quantity int) (pickResult, error) {
+	var res pickResult
+	for len(res.reviewers) < quantity {
+		need, ok := seniorityNeed(settings, assigned)
+		if !ok {
+			break
+		}
+
+		user, isFallback, err := s.pickFitting(ctx, pool, settings, authorID, slices.Concat(exclude, res.reviewers), need.fits)
+		if err != nil {
+			return res, err
+		}
+		if user == nil {
+			break
+		}
+
+		res.reviewers = append(res.reviewers, user.ID)
+		if isFallback {
+			res.fallback = append(res.fallback, user.ID)
+		}
+		assigned = append(assigned, *user)
+	}
+	return res, nil
+}
+
+// pickFitting picks one available candidate who fits, the team of settings is asked first,
+// backup teams in declared order after it. nil means nobody fits.
+func (s *Service) pickFitting(ctx context.Context, pool *candidatePool, settings domain.TeamSettings, authorID string, exclude []string, fits func(domain.User) bool) (*domain.User, bool, error) {
+	prefer := preferenceOf(settings, time.Now())
+	for i, teamName := range append([]string{settings.TeamName}, settings.BackupTeams...) {
+		candidates, _, err := s.availableCandidates(ctx, pool, teamName, exclude)
+		if err != nil {
+			return nil, false, err
+		}
+
+		fitting := make([]domain.User, 0, len(candidates))
+		for _, user := range candidates {
+			if fits(user) {
+				fitting = append(fitting, user)
+			}
+		}
+
+		chosen, err := s.selectPreferred(ctx, pool, SelectInput{
+			TeamName:   teamName,
+			Strategy:   settings.Strategy,
+			AuthorID:   authorID,
+			Candidates: fitting,
+			Quantity:   1,
+			Counter:    pool,
+		}, prefer)
+		if err != nil {
+			return nil, false, err
+		}
+		if len(chosen) == 0 {
+			continue
+		}
+
+		for _, user := range fitting {
+			if user.ID == chosen[0] {
+				return &user, i > 0, nil
+			}
+		}
+	}
+	return nil, false, nil
+}
+
+// reviewerUsers returns users of the reviewers, see candidatePool.user.
+func reviewerUsers(ctx context.Context, pool *candidatePool, ids []string) ([]domain.User, error) {
+	out := make([]domain.User, 0, len(ids))
+	for _, id := range ids {
+		user, err := pool.user(ctx, id)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, user)
+	}
+	return out, nil
+}
+
+// SetUserSeniority changes seniority of the member of the team, empty means unknown.
+func (s *Service) SetUserSeniority(ctx context.Context, teamName, userID string, seniority domain.Seniority) (*domain.User, error) {
+	seniority, err := normalizeSeniority(seniority)
+	if err != nil {
+		return nil, err
+	}
+
+	user, err := s.userStore.GetUserByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if user.TeamName != teamName {
+		return nil, fmt.Errorf("%w: user %q is not a member of team %q", domain.ErrNotFound, userID, teamName)
+	}
+
+	if err := s.userStore.SetSeniority(ctx, userID, seniority); err != nil {
+		return nil, err
+	}
+
+	user.Seniority = seniority
+	return user, nil
+}
+
+// normalizeSeniority accepts any case, empty means unknown.
+func normalizeSeniority(value domain.Seniority) (domain.Seniority, error) {
+	if value == "" {
+		return "", nil
+	}
+	seniority := domain.Seniority(strings.ToUpper(string(value)))
+	if !seniority.IsValid() {
+		return "", fmt.Errorf("%w: unknown seniority %q", domain.ErrInvalidInput, value)
+	}
+	return seniority, nil
+}
@@ -0,0 +1,203 @@
+package service
+
+import (
+	"avito/internal/domain"
+	"avito/internal/service/mocks"
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestService_CreatePullRequest_SeniorityPolicy(t *testing.T) {
+	ctx := context.Background()
+
+	author := domain.User{ID: "u1", TeamName: "team-A", IsActive: true}
+
+	tests := []struct {
+		name       string
+		settings   func(*domain.TeamSettings)
+		members    []domain.User
+		wantSubset []string
+		wantErr    string
+	}{
+		{
+			name:     "senior_is_picked_first",
+			settings: func(s *domain.TeamSettings) { s.RequireSenior = true },
+			members: []domain.User{
+				{ID: "j1", TeamName: "team-A", IsActive: true, Seniority: domain.SeniorityJunior},
+				{ID: "m1", TeamName: "team-A", IsActive: true, Seniority: domain.SeniorityMiddle},
+				{ID: "m2", TeamName: "team-A", IsActive: true, Seniority: domain.SeniorityMiddle},
+				{ID: "l1", TeamName: "team-A", IsActive: true, Seniority: domain.SeniorityLead},
+			},
+			wantSubset: []string{"l1"},
+		},
+		{
+			name:     "no_senior",
+			settings: func(s *domain.TeamSettings) { s.RequireSenior = true },
+			members: []domain.User{
+				{ID: "m1", TeamName: "team-A", IsActive: true, Seniority: domain.SeniorityMiddle},
+				{ID: "m2", TeamName: "team-A", IsActive: true, Seniority: domain.SeniorityMiddle},
+			},
+			wantErr: "at least one senior or lead reviewer is required",
+		},
+		{
+			name:     "junior_gets_a_peer",
+			settings: func(s *domain.TeamSettings) { s.JuniorsNotAlone = true },
+			members: []domain.User{
+				{ID: "j1", TeamName: "team-A", IsActive: true, Seniority: domain.SeniorityJunior},
+				{ID: "j2", TeamName: "team-A", IsActive: true, Seniority: domain.SeniorityJunior},
+				{ID: "j3", TeamName: "team-A", IsActive: true, Seniority: domain.SeniorityJunior},
+				{ID: "x1", TeamName: "team-A", IsActive: true},
+			},
+			wantSubset: []string{"x1"},
+		},
+		{
+			name:     "only_juniors",
+			settings: func(s *domain.TeamSettings) { s.JuniorsNotAlone = true },
+			members: []domain.User{
+				{ID: "j1", TeamName: "team-A", IsActive: true, Seniority: domain.SeniorityJunior},
+				{ID: "j2", TeamName: "team-A", IsActive: true, Seniority: domain.SeniorityJunior},
+			},
+			wantErr: "juniors must not review alone",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			prStore := mocks.NewPullRequestStorage(t)
+			userStore := mocks.NewUserStorage(t)
+			teamStore := mocks.NewTeamStorage(t)
+
+			settings := defaultSettings
+			tt.settings(&settings)
+
+			prStore.
+				On("GetPullRequestByID", ctx, "pr-1").
+				Return(domain.PullRequest{}, domain.ErrNotFound).Once()
+
+			userStore.
+				On("GetUserByID", ctx, "u1").
+				Return(&author, nil).Once()
+
+			teamStore.
+				On("GetTeamSettings", ctx, "team-A").
+				Return(settings, nil).Once()
+
+			userStore.
+				On("ListActiveUserByTeam", ctx, "team-A").
+				Return(append([]domain.User{author}, tt.members...), nil).Once()
+
+			if tt.wantErr == "" {
+				prStore.
+					On("Create", ctx, mock.AnythingOfType("domain.PullRequest")).
+					Return(nil).Once()
+
+				prStore.
+					On("AddAssignmentAudits", ctx, mock.Anything).
+					Return(nil).Once()
+
+				expectDecision(ctx, teamStore, prStore)
+			}
+
+			svc := NewService(teamStore, userStore, prStore, &mockTxManager{}, NewRandomSelector(), nil)
+
+			got, err := svc.CreatePullRequest(ctx, domain.PullRequestCreate{ID: "pr-1", Name: "PR name", AuthorID: "u1"})
+			if tt.wantErr != "" {
+				require.ErrorIs(t, err, domain.ErrSeniorityPolicy)
+				assert.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			require.Len(t, got.AssignedReviewers, 2)
+			assert.Subset(t, got.AssignedReviewers, tt.wantSubset)
+		})
+	}
+}
+
+func TestService_ReassignReviewer_SeniorityPolicy(t *testing.T) {
+	ctx := context.Background()
+
+	settings := defaultSettings
+	settings.RequireSenior = true
+
+	tests := []struct {
+		name      string
+		members   []domain.User
+		wantNewID string
+		wantErr   error
+	}{
+		{
+			name: "replaced_by_senior",
+			members: []domain.User{
+				{ID: "j2", TeamName: "team-A", IsActive: true, Seniority: domain.SeniorityJunior},
+				{ID: "s2", TeamName: "team-A", IsActive: true, Seniority: domain.SenioritySenior},
+			},
+			wantNewID: "s2",
+		},
+		{
+			name: "no_senior_left",
+			members: []domain.User{
+				{ID: "j2", TeamName: "team-A", IsActive: true, Seniority: domain.SeniorityJunior},
+			},
+			wantErr: domain.ErrSeniorityPolicy,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			prStore := mocks.NewPullRequestStorage(t)
+			userStore := mocks.NewUserStorage(t)
+			teamStore := mocks.NewTeamStorage(t)
+
+			prStore.
+				On("GetPullRequestByIDForUpdate", ctx, "pr1").
+				Return(domain.PullRequest{
+					ID:                "pr1",
+					Status:            domain.PRStatusOpen,
+					AuthorID:          "author",
+					AssignedReviewers: []string{"s1", "j1"},
+				}, nil).Once()
+
+			userStore.
+				On("GetUserByID", ctx, "s1").
+				Return(&domain.User{ID: "s1", TeamName: "team-A", IsActive: true, Seniority: domain.SenioritySenior}, nil).Once()
+
+			teamStore.
+				On("GetTeamSettings", ctx, "team-A").
+				Return(settings, nil).Once()
+
+			userStore.
+				On("GetUserByID", ctx, "j1").
+				Return(&domain.User{ID: "j1", TeamName: "team-A", IsActive: true, Seniority: domain.SeniorityJunior}, nil).Once()
+
+			userStore.
+				On("ListActiveUserByTeam", ctx, "team-A").
+				Return(tt.members, nil).Once()
+
+			if tt.wantErr == nil {
+				prStore.
+					On("ReplaceReviewer", ctx, "pr1", "s1", tt.wantNewID, false).
+					Return(nil).Once()
+
+				prStore.
+					On("AddAssignmentAudits", ctx, mock.Anything).
+					Return(nil).Once()
+
+				expectDecision(ctx, teamStore, prStore)
+			}
+
+			svc := NewService(teamStore, userStore, prStore, &mockTxManager{}, NewRandomSelector(), nil)
+
+			_, replacedBy, err := svc.ReassignReviewer(ctx, "pr1", "s1", false)
+			if tt.wantErr != nil {
+				require.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantNewID, replacedBy)
+		})
+	}
+}
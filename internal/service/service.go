@@ -3,8 +3,10 @@ package service
 import (
 	"context"
 	"errors"
+	"fmt"
 	"math/rand/v2"
 	"slices"
+	"sync"
 	"time"
 
 	"avito/internal/domain"
@@ -14,22 +16,58 @@ type TeamStorage interface {
 	TeamExists(ctx context.Context, teamName string) (bool, error)
 	CreateWithMembers(ctx context.Context, team domain.Team) error
 	GetWithMembers(ctx context.Context, teamName string) (*domain.Team, error)
+	GetTeamSettings(ctx context.Context, teamName string) (domain.TeamSettings, error)
+	SaveTeamSettings(ctx context.Context, settings domain.TeamSettings) error
+	GetCodeOwners(ctx context.Context, teamName string) (string, error)
+	SaveCodeOwners(ctx context.Context, teamName, rules string) error
 }
 
 type UserStorage interface {
 	GetUserByID(ctx context.Context, userID string) (*domain.User, error)
+	GetUsersByIDs(ctx context.Context, userIDs []string) ([]domain.User, error)
 	SetIsActive(ctx context.Context, userID string, isActive bool) error
+	SetIsActiveMany(ctx context.Context, userIDs []string, isActive bool) error
+	SetMaxOpenReviews(ctx context.Context, userID string, maxOpenReviews *int) error
+	SetWorkingHours(ctx context.Context, userID, timezone string, hours *domain.WorkingHours) error
+	SetTags(ctx context.Context, userID string, tags []string) error
+	SetSeniority(ctx context.Context, userID string, seniority domain.Seniority) error
 	ListActiveUserByTeam(ctx context.Context, teamName string) ([]domain.User, error)
+
+	AddAbsence(ctx context.Context, absence domain.Absence) (int64, error)
+	GetAbsenceByID(ctx context.Context, absenceID int64) (domain.Absence, error)
+	ListAbsences(ctx context.Context, userID string) ([]domain.Absence, error)
+	CancelAbsence(ctx context.Context, absenceID int64, cancelledAt time.Time) error
 }
 
 type PullRequestStorage interface {
-	ListByReviewer(ctx context.Context, userID string) ([]domain.PullRequest, error)
+	ListPullRequests(ctx context.Context, filter domain.PullRequestFilter) ([]domain.PullRequest, error)
+	ListByStatus(ctx context.Context, status domain.PullRequestStatus, limit int) ([]domain.PullRequest, error)
+	ListUnderstaffed(ctx context.Context, limit int) ([]domain.PullRequest, error)
+	MarkBackfillAttempted(ctx context.Context, pullRequestIDs []string) error
+	ListOpenByReviewersForUpdate(ctx context.Context, userIDs []string) ([]domain.PullRequest, error)
+	CountOpenReviews(ctx context.Context, userIDs []string) (map[string]int, error)
+	RecentReviewers(ctx context.Context, authorID string, limit int) ([]string, error)
+	RecentReviewersByAuthors(ctx context.Context, authorIDs []string, limit int) (map[string][]string, error)
 
 	GetPullRequestByID(ctx context.Context, pullRequestID string) (domain.PullRequest, error)
 	GetPullRequestByIDForUpdate(ctx context.Context, pullRequestID string) (domain.PullRequest, error)
 	Create(ctx context.Context, pullRequest domain.PullRequest) error
-	UpdateStatusMerged(ctx context.Context, pullRequestID string, mergedAt *time.Time) error
-	ReplaceReviewer(ctx context.Context, pullRequestID string, oldID string, newID string) error
+	UpdateStatusMerged(ctx context.Context, pullRequestID string, mergedAt *time.Time, override *domain.MergeOverride) error
+	UpdateStatus(ctx context.Context, pullRequestID string, status domain.PullRequestStatus) error
+	UpdateStatusClosed(ctx context.Context, pullRequestID string, closedAt time.Time) error
+	MarkReady(ctx context.Context, pullRequest domain.PullRequest) error
+	UpdateMetadata(ctx context.Context, pullRequest domain.PullRequest) error
+	SetLabels(ctx context.Context, pullRequestID string, labels []string) error
+	AddReviewer(ctx context.Context, pullRequestID string, userID string, isFallback, isMandatory bool) error
+	ReplaceReviewer(ctx context.Context, pullRequestID string, oldID string, newID string, isFallback bool) error
+	ReplaceReviewers(ctx context.Context, reassignments []domain.Reassignment) error
+	RemoveReviewer(ctx context.Context, pullRequestID string, userID string) error
+	SetReviewState(ctx context.Context, pullRequestID string, userID string, state domain.ReviewState, at time.Time) error
+
+	AddAssignmentAudits(ctx context.Context, audits []domain.AssignmentAudit) error
+	ListAssignmentAudits(ctx context.Context, pullRequestID string) ([]domain.AssignmentAudit, error)
+	AddAssignmentDecision(ctx context.Context, decision domain.AssignmentDecision) error
+	ListAssignmentDecisions(ctx context.Context, pullRequestID string) ([]domain.AssignmentDecision, error)
 }
 
 type txManager interface {
@@ -41,18 +79,48 @@ type Service struct {
 	userStore UserStorage
 	prStore   PullRequestStorage
 	tx        txManager
+	selector  ReviewerSelector
+
+	seedMu sync.Mutex
+	seeds  rand.Source
 }
 
-func NewService(teamStore TeamStorage, userStore UserStorage, prStore PullRequestStorage, tx txManager) *Service {
+// NewService seeds is the source of reviewer selection seeds, nil means a randomly seeded one.
+func NewService(teamStore TeamStorage, userStore UserStorage, prStore PullRequestStorage, tx txManager, selector ReviewerSelector, seeds rand.Source) *Service {
+	if seeds == nil {
+		seeds = rand.NewPCG(rand.Uint64(), rand.Uint64())
+	}
 	return &Service{
 		teamStore: teamStore,
 		userStore: userStore,
 		prStore:   prStore,
 		tx:        tx,
+		selector:  selector,
+		seeds:     seeds,
 	}
 }
 
+func (s *Service) nextSeed() uint64 {
+	s.seedMu.Lock()
+	defer s.seedMu.Unlock()
+	return s.seeds.Uint64()
+}
+
 func (s *Service) CreateTeam(ctx context.Context, team domain.Team) (*domain.Team, error) {
+	for i, member := range team.Members {
+		tags, err := normalizeTags(member.Tags)
+		if err != nil {
+			return nil, err
+		}
+		team.Members[i].Tags = tags
+
+		seniority, err := normalizeSeniority(member.Seniority)
+		if err != nil {
+			return nil, err
+		}
+		team.Members[i].Seniority = seniority
+	}
+
 	err := s.tx.WithTx(ctx, func(ctx context.Context) error {
 		exists, err := s.teamStore.TeamExists(ctx, team.Name)
 		if err != nil {
@@ -79,6 +147,140 @@ func (s *Service) GetTeam(ctx context.Context, teamName string) (*domain.Team, e
 	return s.teamStore.GetWithMembers(ctx, teamName)
 }
 
+func (s *Service) GetTeamSettings(ctx context.Context, teamName string) (domain.TeamSettings, error) {
+	return s.teamStore.GetTeamSettings(ctx, teamName)
+}
+
+func (s *Service) UpdateTeamSettings(ctx context.Context, teamName string, update domain.TeamSettingsUpdate) (domain.TeamSettings, error) {
+	var result domain.TeamSettings
+
+	err := s.tx.WithTx(ctx, func(ctx context.Context) error {
+		settings, err := s.teamStore.GetTeamSettings(ctx, teamName)
+		if err != nil {
+			return err
+		}
+
+		if update.MinReviewers != nil {
+			settings.MinReviewers = *update.MinReviewers
+		}
+		if update.MaxReviewers != nil {
+			settings.MaxReviewers = *update.MaxReviewers
+		}
+		if update.Strategy != nil {
+			settings.Strategy = *update.Strategy
+		}
+		if update.AllowPartial != nil {
+			settings.AllowPartial = *update.AllowPartial
+		}
+		if update.BackupTeams != nil {
+			settings.BackupTeams = *update.BackupTeams
+		}
+		if update.PreferWorkingHours != nil {
+			settings.PreferWorkingHours = *update.PreferWorkingHours
+		}
+		if update.MandatoryReviewers != nil {
+			settings.MandatoryReviewers = *update.MandatoryReviewers
+		}
+		if update.RequireSenior != nil {
+			settings.RequireSenior = *update.RequireSenior
+		}
+		if update.JuniorsNotAlone != nil {
+			settings.JuniorsNotAlone = *update.JuniorsNotAlone
+		}
+		if update.RequiredApprovals != nil {
+			settings.RequiredApprovals = *update.RequiredApprovals
+		}
+
+		if err := validateTeamSettings(settings); err != nil {
+			return err
+		}
+
+		for _, backupTeam := range settings.BackupTeams {
+			exists, err := s.teamStore.TeamExists(ctx, backupTeam)
+			if err != nil {
+				return err
+			}
+			if !exists {
+				return fmt.Errorf("%w: backup team %q does not exist", domain.ErrInvalidInput, backupTeam)
+			}
+		}
+
+		for _, userID := range settings.MandatoryReviewers {
+			if _, err := s.userStore.GetUserByID(ctx, userID); err != nil {
+				if errors.Is(err, domain.ErrNotFound) {
+					return fmt.Errorf("%w: mandatory reviewer %q does not exist", domain.ErrInvalidInput, userID)
+				}
+				return err
+			}
+		}
+
+		if err := s.teamStore.SaveTeamSettings(ctx, settings); err != nil {
+			return err
+		}
+
+		result = settings
+		return nil
+	})
+	if err != nil {
+		return domain.TeamSettings{}, err
+	}
+
+	return result, nil
+}
+
+// DeactivateTeamUsers deactivates members of the team and replaces them in all their
+// open reviews with remaining active members (or members of backup teams) in one transaction.
+func (s *Service) DeactivateTeamUsers(ctx context.Context, teamName string, userIDs []string) ([]domain.User, []domain.Reassignment, error) {
+	if len(userIDs) == 0 {
+		return nil, nil, fmt.Errorf("%w: user_ids is empty", domain.ErrInvalidInput)
+	}
+
+	var (
+		users         []domain.User
+		reassignments []domain.Reassignment
+	)
+
+	err := s.tx.WithTx(ctx, func(ctx context.Context) error {
+		team, err := s.teamStore.GetWithMembers(ctx, teamName)
+		if err != nil {
+			return err
+		}
+
+		members := make(map[string]domain.User, len(team.Members))
+		for _, member := range team.Members {
+			members[member.ID] = member
+		}
+
+		users = make([]domain.User, 0, len(userIDs))
+		ids := make([]string, 0, len(userIDs))
+		for _, id := range userIDs {
+			if slices.Contains(ids, id) {
+				continue
+			}
+			ids = append(ids, id)
+
+			member, ok := members[id]
+			if !ok {
+				return fmt.Errorf("%w: user %q in team %q", domain.ErrNotFound, id, teamName)
+			}
+			member.IsActive = false
+			users = append(users, member)
+		}
+
+		if err := s.userStore.SetIsActiveMany(ctx, ids, false); err != nil {
+			return err
+		}
+
+		reassignments, err = s.reassignOpenReviews(ctx, users)
+		return err
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return users, reassignments, nil
+}
+
 func (s *Service) SetIsActive(ctx context.Context, userID string, isActive bool) (*domain.User, error) {
 	user, err := s.userStore.GetUserByID(ctx, userID)
 	if err != nil {
@@ -93,18 +295,114 @@ func (s *Service) SetIsActive(ctx context.Context, userID string, isActive bool)
 	return user, nil
 }
 
-func (s *Service) GetUserReviews(ctx context.Context, userID string) ([]domain.PullRequest, error) {
-	if _, err := s.userStore.GetUserByID(ctx, userID); err != nil {
+// DeactivateUser deactivates the user and replaces them in all their open reviews
+// in one transaction. Reviews without candidate keep the user assigned.
+func (s *Service) DeactivateUser(ctx context.Context, userID string) (*domain.User, []domain.Reassignment, error) {
+	var (
+		user          *domain.User
+		reassignments []domain.Reassignment
+	)
+
+	err := s.tx.WithTx(ctx, func(ctx context.Context) error {
+		var err error
+		user, err = s.userStore.GetUserByID(ctx, userID)
+		if err != nil {
+			return err
+		}
+
+		if err := s.userStore.SetIsActive(ctx, userID, false); err != nil {
+			return err
+		}
+		user.IsActive = false
+
+		reassignments, err = s.reassignOpenReviews(ctx, []domain.User{*user})
+		return err
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return user, reassignments, nil
+}
+
+func (s *Service) SetMaxOpenReviews(ctx context.Context, userID string, maxOpenReviews *int) (*domain.User, error) {
+	if maxOpenReviews != nil && *maxOpenReviews < 0 {
+		return nil, fmt.Errorf("%w: max_open_reviews must be >= 0", domain.ErrInvalidInput)
+	}
+
+	user, err := s.userStore.GetUserByID(ctx, userID)
+	if err != nil {
 		return nil, err
 	}
-	return s.prStore.ListByReviewer(ctx, userID)
+
+	if err := s.userStore.SetMaxOpenReviews(ctx, userID, maxOpenReviews); err != nil {
+		return nil, err
+	}
+
+	user.MaxOpenReviews = maxOpenReviews
+	return user, nil
 }
 
-func (s *Service) CreatePullRequest(ctx context.Context, prID, prName, authorID string) (domain.PullRequest, error) {
+const minutesPerDay = 24 * 60
+
+// SetWorkingHours empty timezone means UTC, nil hours make the user always available.
+func (s *Service) SetWorkingHours(ctx context.Context, userID, timezone string, hours *domain.WorkingHours) (*domain.User, error) {
+	if _, err := time.LoadLocation(timezone); err != nil {
+		return nil, fmt.Errorf("%w: unknown timezone %q", domain.ErrInvalidInput, timezone)
+	}
+	if hours != nil {
+		if hours.Start < 0 || hours.Start >= minutesPerDay || hours.End < 0 || hours.End >= minutesPerDay {
+			return nil, fmt.Errorf("%w: working hours must be within a day", domain.ErrInvalidInput)
+		}
+		if hours.Start == hours.End {
+			return nil, fmt.Errorf("%w: working hours must not be empty", domain.ErrInvalidInput)
+		}
+	}
+
+	user, err := s.userStore.GetUserByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.userStore.SetWorkingHours(ctx, userID, timezone, hours); err != nil {
+		return nil, err
+	}
+
+	user.Timezone = timezone
+	user.WorkingHours = hours
+	return user, nil
+}
+
+// GetUserReviews returns one page of PRs reviewed by the user, oldest first. Statuses, Labels,
+// Limit and After of filter are used, zero Limit means defaultPageSize.
+func (s *Service) GetUserReviews(ctx context.Context, userID string, filter domain.PullRequestFilter) (domain.PullRequestPage, error) {
+	filter = domain.PullRequestFilter{
+		Statuses:   filter.Statuses,
+		ReviewerID: userID,
+		Labels:     filter.Labels,
+		Limit:      filter.Limit,
+		After:      filter.After,
+	}
+	if err := normalizePullRequestFilter(&filter); err != nil {
+		return domain.PullRequestPage{}, err
+	}
+	if _, err := s.userStore.GetUserByID(ctx, userID); err != nil {
+		return domain.PullRequestPage{}, err
+	}
+	return s.listPage(ctx, filter)
+}
+
+func (s *Service) CreatePullRequest(ctx context.Context, in domain.PullRequestCreate) (domain.PullRequest, error) {
 	var created domain.PullRequest
 
-	err := s.tx.WithTx(ctx, func(ctx context.Context) error {
-		_, err := s.prStore.GetPullRequestByID(ctx, prID)
+	requiredTags, err := normalizeTags(in.RequiredTags)
+	if err != nil {
+		return created, err
+	}
+	in.RequiredTags = requiredTags
+
+	err = s.tx.WithTx(ctx, func(ctx context.Context) error {
+		_, err := s.prStore.GetPullRequestByID(ctx, in.ID)
 		if err == nil {
 			return domain.ErrPRExists
 		}
@@ -112,31 +410,38 @@ func (s *Service) CreatePullRequest(ctx context.Context, prID, prName, authorID
 			return err
 		}
 
-		author, err := s.userStore.GetUserByID(ctx, authorID)
-		if err != nil {
-			return err
+		if in.Draft {
+			if _, err := s.userStore.GetUserByID(ctx, in.AuthorID); err != nil {
+				return err
+			}
+
+			now := time.Now().UTC()
+			created = domain.PullRequest{
+				ID:        in.ID,
+				Name:      in.Name,
+				AuthorID:  in.AuthorID,
+				Status:    domain.PRStatusDraft,
+				CreatedAt: &now,
+			}
+			return s.prStore.Create(ctx, created)
 		}
 
-		candidates, err := s.userStore.ListActiveUserByTeam(ctx, author.TeamName)
+		pool := s.newCandidatePool()
+		pr, decision, err := s.planPullRequest(ctx, pool, in, time.Now().UTC())
 		if err != nil {
 			return err
 		}
 
-		candidates = filterUsersExclude(candidates, []string{authorID})
-		reviewers := chooseReviewers(candidates, 2)
-		now := time.Now().UTC()
+		if err := s.prStore.Create(ctx, pr); err != nil {
+			return err
+		}
 
-		pr := domain.PullRequest{
-			ID:                prID,
-			Name:              prName,
-			AuthorID:          authorID,
-			Status:            domain.PRStatusOpen,
-			AssignedReviewers: reviewers,
-			CreatedAt:         &now,
-			MergedAt:          nil,
+		audit := pool.audit(pr.ID, domain.AssignmentCreate, *pr.CreatedAt)
+		if err := s.prStore.AddAssignmentAudits(ctx, []domain.AssignmentAudit{audit}); err != nil {
+			return err
 		}
 
-		if err := s.prStore.Create(ctx, pr); err != nil {
+		if err := s.prStore.AddAssignmentDecision(ctx, decision); err != nil {
 			return err
 		}
 
@@ -151,8 +456,87 @@ func (s *Service) CreatePullRequest(ctx context.Context, prID, prName, authorID
 	return created, nil
 }
 
-func (s *Service) MergePullRequest(ctx context.Context, prID string) (domain.PullRequest, error) {
-	var result domain.PullRequest
+// PreviewPullRequest chooses reviewers like CreatePullRequest does but writes nothing
+// and does not move round-robin rotation. Randomized strategies may choose others on create.
+func (s *Service) PreviewPullRequest(ctx context.Context, in domain.PullRequestCreate) (domain.PullRequestPreview, error) {
+	requiredTags, err := normalizeTags(in.RequiredTags)
+	if err != nil {
+		return domain.PullRequestPreview{}, err
+	}
+	in.RequiredTags = requiredTags
+
+	pool := s.newCandidatePool()
+	pool.dryRun = true
+	pr, decision, err := s.planPullRequest(ctx, pool, in, time.Now().UTC())
+	if err != nil {
+		return domain.PullRequestPreview{}, err
+	}
+
+	return domain.PullRequestPreview{
+		PullRequest: pr,
+		Decision:    decision,
+	}, nil
+}
+
+// planPullRequest chooses reviewers of the new PR with the pool, in.RequiredTags must be normalized.
+// Returned PR and decision are not written.
+func (s *Service) planPullRequest(ctx context.Context, pool *candidatePool, in domain.PullRequestCreate, now time.Time) (domain.PullRequest, domain.AssignmentDecision, error) {
+	author, err := s.userStore.GetUserByID(ctx, in.AuthorID)
+	if err != nil {
+		return domain.PullRequest{}, domain.AssignmentDecision{}, err
+	}
+
+	settings, err := s.teamStore.GetTeamSettings(ctx, author.TeamName)
+	if err != nil {
+		return domain.PullRequest{}, domain.AssignmentDecision{}, err
+	}
+
+	pool.begin(in.ID)
+	picked, err := s.pickForPullRequest(ctx, pool, settings, in.AuthorID, nil, in.ChangedFiles, in.RequiredTags)
+	if err != nil {
+		return domain.PullRequest{}, domain.AssignmentDecision{}, err
+	}
+
+	status := domain.PRStatusOpen
+	if len(picked.reviewers) < settings.MinReviewers {
+		switch {
+		case picked.atCapacity:
+			status = domain.PRStatusNeedsReviewers // will be staffed when someone's load drops
+		case !settings.AllowPartial:
+			return domain.PullRequest{}, domain.AssignmentDecision{}, domain.ErrNoCandidate
+		}
+	}
+
+	pr := domain.PullRequest{
+		ID:                 in.ID,
+		Name:               in.Name,
+		AuthorID:           in.AuthorID,
+		Status:             status,
+		AssignedReviewers:  picked.reviewers,
+		FallbackReviewers:  picked.fallback,
+		CreatedAt:          &now,
+		MandatoryReviewers: picked.mandatory,
+		MergedAt:           nil,
+		RequiredTags:       in.RequiredTags,
+		MatchedTags:        matchedTags(pool, picked.reviewers, in.RequiredTags),
+	}
+
+	decision, err := s.decide(ctx, pool, in.ID, domain.AssignmentCreate, settings, in.AuthorID, picked.reviewers, picked.fallback, nil, "", now)
+	if err != nil {
+		return domain.PullRequest{}, domain.AssignmentDecision{}, err
+	}
+
+	return pr, decision, nil
+}
+
+// MergePullRequest checks the approval rule of the author's team, override merges
+// anyway and is stored with PR when the rule is not satisfied. Waiting PRs are
+// staffed after the merge is committed.
+func (s *Service) MergePullRequest(ctx context.Context, prID string, override *domain.MergeOverride) (domain.PullRequest, error) {
+	var (
+		result domain.PullRequest
+		merged bool
+	)
 
 	err := s.tx.WithTx(ctx, func(ctx context.Context) error {
 		pr, err := s.prStore.GetPullRequestByIDForUpdate(ctx, prID)
@@ -164,16 +548,30 @@ func (s *Service) MergePullRequest(ctx context.Context, prID string) (domain.Pul
 			result = pr
 			return nil
 		}
+		if err := checkTransition(pr.Status, domain.PRStatusMerged); err != nil {
+			return err
+		}
+
+		settings, err := s.authorSettings(ctx, pr)
+		if err != nil {
+			return err
+		}
+		if err := checkApprovals(settings, pr); err == nil {
+			override = nil
+		} else if override == nil {
+			return err
+		}
 
 		now := time.Now().UTC()
-		if err := s.prStore.UpdateStatusMerged(ctx, prID, &now); err != nil {
+		if err := s.prStore.UpdateStatusMerged(ctx, prID, &now, override); err != nil {
 			return err
 		}
 
 		pr.Status = domain.PRStatusMerged
 		pr.MergedAt = &now
+		pr.MergeOverride = override
 		result = pr
-
+		merged = true
 		return nil
 	})
 
@@ -181,10 +579,16 @@ func (s *Service) MergePullRequest(ctx context.Context, prID string) (domain.Pul
 		return result, err
 	}
 
+	if merged {
+		// reviewers of the merged PR have one review less now
+		s.staffWaitingPullRequests(ctx)
+	}
+
 	return result, nil
 }
 
-func (s *Service) ReassignReviewer(ctx context.Context, prID, oldUserID string) (domain.PullRequest, string, error) {
+// ReassignReviewer mandatory reviewer is replaced only with force.
+func (s *Service) ReassignReviewer(ctx context.Context, prID, oldUserID string, force bool) (domain.PullRequest, string, error) {
 	var (
 		result     domain.PullRequest
 		replacedBy string
@@ -196,45 +600,65 @@ func (s *Service) ReassignReviewer(ctx context.Context, prID, oldUserID string)
 			return err
 		}
 
-		if pr.Status == domain.PRStatusMerged {
-			return domain.ErrPRMerged
+		if err := requireOpen(pr); err != nil {
+			return err
 		}
 
 		if !slices.Contains(pr.AssignedReviewers, oldUserID) {
 			return domain.ErrNotAssigned
 		}
 
+		if !force && slices.Contains(pr.MandatoryReviewers, oldUserID) {
+			return domain.ErrMandatoryReviewer
+		}
+
 		oldUser, err := s.userStore.GetUserByID(ctx, oldUserID)
 		if err != nil {
 			return err
 		}
 
-		candidates, err := s.userStore.ListActiveUserByTeam(ctx, oldUser.TeamName)
+		pool := s.newCandidatePool()
+		pool.begin(prID)
+		assigned := slices.DeleteFunc(slices.Clone(pr.AssignedReviewers), func(id string) bool {
+			return id == oldUserID
+		})
+		reassignment, err := s.planReplacement(ctx, pool, &pr, *oldUser, nil)
 		if err != nil {
 			return err
 		}
-		exclude := append([]string{oldUserID, pr.AuthorID}, pr.AssignedReviewers...)
-		candidates = filterUsersExclude(candidates, exclude)
-		if len(candidates) == 0 {
+		if reassignment.NewReviewerID == "" {
 			return domain.ErrNoCandidate
 		}
 
-		newReviewer := chooseReviewers(candidates, 1)[0]
-		newID := newReviewer
+		err = s.prStore.ReplaceReviewer(ctx, prID, oldUserID, reassignment.NewReviewerID, reassignment.IsFallback)
+		if err != nil {
+			return err
+		}
 
-		if err := s.prStore.ReplaceReviewer(ctx, prID, oldUserID, newID); err != nil {
+		now := time.Now().UTC()
+		audit := pool.audit(prID, domain.AssignmentReassign, now)
+		if err := s.prStore.AddAssignmentAudits(ctx, []domain.AssignmentAudit{audit}); err != nil {
 			return err
 		}
 
-		for i, id := range pr.AssignedReviewers {
-			if id == oldUserID {
-				pr.AssignedReviewers[i] = newID
-				break
-			}
+		settings, err := pool.teamSettings(ctx, oldUser.TeamName)
+		if err != nil {
+			return err
+		}
+		var fallback []string
+		if reassignment.IsFallback {
+			fallback = []string{reassignment.NewReviewerID}
+		}
+		decision, err := s.decide(ctx, pool, prID, domain.AssignmentReassign, settings, pr.AuthorID, []string{reassignment.NewReviewerID}, fallback, assigned, oldUserID, now)
+		if err != nil {
+			return err
+		}
+		if err := s.prStore.AddAssignmentDecision(ctx, decision); err != nil {
+			return err
 		}
 
 		result = pr
-		replacedBy = newID
+		replacedBy = reassignment.NewReviewerID
 		return nil
 	})
 
@@ -245,7 +669,49 @@ func (s *Service) ReassignReviewer(ctx context.Context, prID, oldUserID string)
 	return result, replacedBy, nil
 }
 
-func chooseReviewers(candidates []domain.User, quantity int) []string {
+const maxReviewersLimit = 10
+
+func validateTeamSettings(settings domain.TeamSettings) error {
+	if settings.MinReviewers < 0 {
+		return fmt.Errorf("%w: min_reviewers must be >= 0", domain.ErrInvalidInput)
+	}
+	if settings.MaxReviewers < settings.MinReviewers {
+		return fmt.Errorf("%w: max_reviewers must be >= min_reviewers", domain.ErrInvalidInput)
+	}
+	if settings.MaxReviewers > maxReviewersLimit {
+		return fmt.Errorf("%w: max_reviewers must be <= %d", domain.ErrInvalidInput, maxReviewersLimit)
+	}
+	if hasSeniorityPolicy(settings) && settings.MaxReviewers == 0 {
+		return fmt.Errorf("%w: seniority policy needs max_reviewers >= 1", domain.ErrInvalidInput)
+	}
+	if settings.RequiredApprovals < 0 || settings.RequiredApprovals > settings.MaxReviewers {
+		return fmt.Errorf("%w: required_approvals must be between 0 and max_reviewers", domain.ErrInvalidInput)
+	}
+	if len(settings.MandatoryReviewers) > settings.MaxReviewers {
+		return fmt.Errorf("%w: mandatory_reviewers must fit into max_reviewers", domain.ErrInvalidInput)
+	}
+	for i, userID := range settings.MandatoryReviewers {
+		if slices.Contains(settings.MandatoryReviewers[:i], userID) {
+			return fmt.Errorf("%w: duplicate mandatory reviewer %q", domain.ErrInvalidInput, userID)
+		}
+	}
+	if settings.Strategy != "" && !settings.Strategy.IsValid() {
+		return fmt.Errorf("%w: unknown strategy %q", domain.ErrInvalidInput, settings.Strategy)
+	}
+	seen := make(map[string]struct{}, len(settings.BackupTeams))
+	for _, backupTeam := range settings.BackupTeams {
+		if backupTeam == settings.TeamName {
+			return fmt.Errorf("%w: team can not be its own backup team", domain.ErrInvalidInput)
+		}
+		if _, ok := seen[backupTeam]; ok {
+			return fmt.Errorf("%w: duplicate backup team %q", domain.ErrInvalidInput, backupTeam)
+		}
+		seen[backupTeam] = struct{}{}
+	}
+	return nil
+}
+
+func chooseReviewers(rng *rand.Rand, candidates []domain.User, quantity int) []string {
 	if len(candidates) == 0 || quantity <= 0 {
 		return nil
 	}
@@ -258,7 +724,7 @@ func chooseReviewers(candidates []domain.User, quantity int) []string {
 		return outIDs
 	}
 
-	perm := rand.Perm(len(candidates))
+	perm := rng.Perm(len(candidates))
 
 	outIDs := make([]string, 0, quantity)
 	for _, randIndex := range perm {
@@ -3,22 +3,1210 @@ package service
 import (
 	"avito/internal/domain"
 	"avito/internal/service/mocks"
+	"cmp"
 	"context"
 	"errors"
+	"fmt"
 	"github.com/stretchr/testify/mock"
+	"math/rand/v2"
+	"slices"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
 
+var defaultSettings = domain.TeamSettings{
+	TeamName:     "team-A",
+	MinReviewers: 2,
+	MaxReviewers: 2,
+	AllowPartial: true,
+}
+
 type mockTxManager struct{}
 
-func (f *mockTxManager) WithTx(ctx context.Context, fn func(ctx context.Context) error) error {
-	return fn(ctx)
+func (f *mockTxManager) WithTx(ctx context.Context, fn func(ctx context.Context) error) error {
+	return fn(ctx)
+}
+
+func TestService_MergePullRequest_Idempotent(t *testing.T) {
+	ctx := context.Background()
+
+	prStore := mocks.NewPullRequestStorage(t)
+	userStore := mocks.NewUserStorage(t)
+	teamStore := mocks.NewTeamStorage(t)
+	tx := &mockTxManager{}
+
+	pr := domain.PullRequest{
+		ID:       "pr1",
+		AuthorID: "author",
+		Status:   domain.PRStatusOpen,
+		MergedAt: nil,
+	}
+
+	prStore.
+		On("GetPullRequestByIDForUpdate", ctx, "pr1").
+		Return(pr, nil).Once()
+
+	userStore.
+		On("GetUserByID", ctx, "author").
+		Return(&domain.User{ID: "author", TeamName: "team-A"}, nil).Once()
+
+	teamStore.
+		On("GetTeamSettings", ctx, "team-A").
+		Return(defaultSettings, nil).Once()
+
+	prStore.
+		On("UpdateStatusMerged", ctx, "pr1", mock.AnythingOfType("*time.Time"), (*domain.MergeOverride)(nil)).
+		Return(nil).Once()
+
+	prStore.
+		On("ListByStatus", ctx, domain.PRStatusNeedsReviewers, staffBatchSize).
+		Return([]domain.PullRequest{}, nil).Once()
+
+	svc := NewService(teamStore, userStore, prStore, tx, NewRandomSelector(), nil)
+
+	got1, err1 := svc.MergePullRequest(ctx, "pr1", nil)
+	require.NoError(t, err1)
+	assert.Equal(t, domain.PRStatusMerged, got1.Status)
+	assert.NotNil(t, got1.MergedAt)
+
+	mergedPR := got1
+	prStore.
+		On("GetPullRequestByIDForUpdate", ctx, "pr1").
+		Return(mergedPR, nil).Once()
+
+	got2, err2 := svc.MergePullRequest(ctx, "pr1", nil)
+	require.NoError(t, err2)
+	assert.Equal(t, domain.PRStatusMerged, got2.Status)
+	assert.Equal(t, got1.MergedAt, got2.MergedAt)
+
+	prStore.AssertExpectations(t)
+}
+
+func TestService_MergePullRequest_StaffsWaitingPullRequests(t *testing.T) {
+	ctx := context.Background()
+
+	prStore := mocks.NewPullRequestStorage(t)
+	userStore := mocks.NewUserStorage(t)
+	teamStore := mocks.NewTeamStorage(t)
+	tx := &mockTxManager{}
+
+	capacity := 1
+	reviewer := domain.User{ID: "r1", TeamName: "team-A", IsActive: true, MaxOpenReviews: &capacity}
+
+	waiting := domain.PullRequest{
+		ID:       "pr2",
+		AuthorID: "u1",
+		Status:   domain.PRStatusNeedsReviewers,
+	}
+
+	settings := defaultSettings
+	settings.MinReviewers = 1
+
+	prStore.
+		On("GetPullRequestByIDForUpdate", ctx, "pr1").
+		Return(domain.PullRequest{ID: "pr1", AuthorID: "author", Status: domain.PRStatusOpen, AssignedReviewers: []string{"r1"}}, nil).Once()
+
+	userStore.
+		On("GetUserByID", ctx, "author").
+		Return(&domain.User{ID: "author", TeamName: "team-A"}, nil).Once()
+
+	teamStore.
+		On("GetTeamSettings", ctx, "team-A").
+		Return(defaultSettings, nil).Once()
+
+	prStore.
+		On("UpdateStatusMerged", ctx, "pr1", mock.AnythingOfType("*time.Time"), (*domain.MergeOverride)(nil)).
+		Return(nil).Once()
+
+	prStore.
+		On("ListByStatus", ctx, domain.PRStatusNeedsReviewers, staffBatchSize).
+		Return([]domain.PullRequest{waiting}, nil).Once()
+
+	prStore.
+		On("MarkBackfillAttempted", ctx, []string{"pr2"}).
+		Return(nil).Once()
+
+	prStore.
+		On("GetPullRequestByIDForUpdate", ctx, "pr2").
+		Return(waiting, nil).Once()
+
+	userStore.
+		On("GetUserByID", ctx, "u1").
+		Return(&domain.User{ID: "u1", TeamName: "team-A", IsActive: true}, nil).Once()
+
+	teamStore.
+		On("GetTeamSettings", ctx, "team-A").
+		Return(settings, nil).Once()
+
+	userStore.
+		On("ListActiveUserByTeam", ctx, "team-A").
+		Return([]domain.User{reviewer}, nil).Once()
+
+	prStore.
+		On("CountOpenReviews", ctx, []string{"r1"}).
+		Return(map[string]int{}, nil).Once()
+
+	prStore.
+		On("AddReviewer", ctx, "pr2", "r1", false, false).
+		Return(nil).Once()
+
+	prStore.
+		On("AddAssignmentAudits", ctx, mock.Anything).
+		Return(nil).Once()
+
+	prStore.
+		On("UpdateStatus", ctx, "pr2", domain.PRStatusOpen).
+		Return(nil).Once()
+
+	svc := NewService(teamStore, userStore, prStore, tx, NewRandomSelector(), nil)
+
+	got, err := svc.MergePullRequest(ctx, "pr1", nil)
+	require.NoError(t, err)
+	assert.Equal(t, domain.PRStatusMerged, got.Status)
+}
+
+func TestService_MergePullRequest_StaffingFailureDoesNotFailMerge(t *testing.T) {
+	ctx := context.Background()
+
+	prStore := mocks.NewPullRequestStorage(t)
+	userStore := mocks.NewUserStorage(t)
+	teamStore := mocks.NewTeamStorage(t)
+
+	prStore.
+		On("GetPullRequestByIDForUpdate", ctx, "pr1").
+		Return(domain.PullRequest{ID: "pr1", AuthorID: "author", Status: domain.PRStatusOpen}, nil).Once()
+
+	userStore.
+		On("GetUserByID", ctx, "author").
+		Return(&domain.User{ID: "author", TeamName: "team-A"}, nil).Once()
+
+	teamStore.
+		On("GetTeamSettings", ctx, "team-A").
+		Return(defaultSettings, nil).Once()
+
+	prStore.
+		On("UpdateStatusMerged", ctx, "pr1", mock.AnythingOfType("*time.Time"), (*domain.MergeOverride)(nil)).
+		Return(nil).Once()
+
+	prStore.
+		On("ListByStatus", ctx, domain.PRStatusNeedsReviewers, staffBatchSize).
+		Return([]domain.PullRequest{{ID: "pr2"}, {ID: "pr3"}}, nil).Once()
+
+	prStore.
+		On("MarkBackfillAttempted", ctx, []string{"pr2", "pr3"}).
+		Return(nil).Once()
+
+	prStore.
+		On("GetPullRequestByIDForUpdate", ctx, "pr2").
+		Return(domain.PullRequest{}, errors.New("db is down")).Once()
+
+	prStore.
+		On("GetPullRequestByIDForUpdate", ctx, "pr3").
+		Return(domain.PullRequest{ID: "pr3", Status: domain.PRStatusMerged}, nil).Once()
+
+	svc := NewService(teamStore, userStore, prStore, &mockTxManager{}, NewRandomSelector(), nil)
+
+	got, err := svc.MergePullRequest(ctx, "pr1", nil)
+	require.NoError(t, err)
+	assert.Equal(t, domain.PRStatusMerged, got.Status)
+}
+
+func TestService_StaffWaitingPullRequests_RotatesUnstaffable(t *testing.T) {
+	ctx := context.Background()
+
+	prStore := mocks.NewPullRequestStorage(t)
+	userStore := mocks.NewUserStorage(t)
+	teamStore := mocks.NewTeamStorage(t)
+
+	// the author is alone in the team, no PR can be staffed
+	prs := make([]domain.PullRequest, staffBatchSize+1)
+	for i := range prs {
+		prs[i] = domain.PullRequest{ID: fmt.Sprintf("pr%03d", i), AuthorID: "u1", Status: domain.PRStatusNeedsReviewers}
+	}
+	attempts := make(map[string]int)
+	batch := 0
+
+	prStore.
+		On("ListByStatus", ctx, domain.PRStatusNeedsReviewers, staffBatchSize).
+		Return(func(_ context.Context, _ domain.PullRequestStatus, limit int) []domain.PullRequest {
+			sorted := slices.Clone(prs)
+			slices.SortStableFunc(sorted, func(a, b domain.PullRequest) int {
+				return cmp.Compare(attempts[a.ID], attempts[b.ID])
+			})
+			return sorted[:limit]
+		}, nil).Twice()
+
+	prStore.
+		On("MarkBackfillAttempted", ctx, mock.Anything).
+		Return(func(_ context.Context, ids []string) error {
+			batch++
+			for _, id := range ids {
+				attempts[id] = batch
+			}
+			return nil
+		}).Twice()
+
+	prStore.
+		On("GetPullRequestByIDForUpdate", ctx, mock.Anything).
+		Return(func(_ context.Context, id string) domain.PullRequest {
+			return domain.PullRequest{ID: id, AuthorID: "u1", Status: domain.PRStatusNeedsReviewers}
+		}, nil)
+
+	userStore.
+		On("GetUserByID", ctx, "u1").
+		Return(&domain.User{ID: "u1", TeamName: "team-A", IsActive: true}, nil)
+
+	teamStore.
+		On("GetTeamSettings", ctx, "team-A").
+		Return(defaultSettings, nil)
+
+	userStore.
+		On("ListActiveUserByTeam", ctx, "team-A").
+		Return([]domain.User{{ID: "u1", TeamName: "team-A", IsActive: true}}, nil)
+
+	svc := NewService(teamStore, userStore, prStore, &mockTxManager{}, NewRandomSelector(), nil)
+
+	svc.staffWaitingPullRequests(ctx)
+	svc.staffWaitingPullRequests(ctx)
+
+	assert.Equal(t, 2, attempts[prs[len(prs)-1].ID], "the PR past the first batch is tried after the next merge")
+}
+
+func TestService_BackfillReviewers_TopsUpUnderstaffed(t *testing.T) {
+	ctx := context.Background()
+
+	prStore := mocks.NewPullRequestStorage(t)
+	userStore := mocks.NewUserStorage(t)
+	teamStore := mocks.NewTeamStorage(t)
+	tx := &mockTxManager{}
+
+	lonely := domain.PullRequest{ID: "pr1", AuthorID: "u1", Status: domain.PRStatusOpen}
+
+	prStore.
+		On("ListUnderstaffed", ctx, backfillBatchSize).
+		Return([]domain.PullRequest{lonely}, nil).Once()
+
+	prStore.
+		On("MarkBackfillAttempted", ctx, []string{"pr1"}).
+		Return(nil).Once()
+
+	prStore.
+		On("GetPullRequestByIDForUpdate", ctx, "pr1").
+		Return(lonely, nil).Once()
+
+	userStore.
+		On("GetUserByID", ctx, "u1").
+		Return(&domain.User{ID: "u1", TeamName: "team-A", IsActive: true}, nil).Once()
+
+	teamStore.
+		On("GetTeamSettings", ctx, "team-A").
+		Return(defaultSettings, nil).Once()
+
+	userStore.
+		On("ListActiveUserByTeam", ctx, "team-A").
+		Return([]domain.User{
+			{ID: "u1", TeamName: "team-A", IsActive: true},
+			{ID: "u2", TeamName: "team-A", IsActive: true},
+		}, nil).Once()
+
+	prStore.
+		On("AddReviewer", ctx, "pr1", "u2", false, false).
+		Return(nil).Once()
+
+	prStore.
+		On("AddAssignmentAudits", ctx, mock.Anything).
+		Return(nil).Once()
+
+	svc := NewService(teamStore, userStore, prStore, tx, NewRandomSelector(), nil)
+
+	got, err := svc.BackfillReviewers(ctx, "")
+	require.NoError(t, err)
+	require.Len(t, got, 1)
+	assert.Equal(t, []string{"u2"}, got[0].AddedReviewers)
+	assert.Equal(t, []string{"u2"}, got[0].PullRequest.AssignedReviewers)
+}
+
+func TestService_BackfillReviewers_ContinuesAfterFailure(t *testing.T) {
+	ctx := context.Background()
+
+	prStore := mocks.NewPullRequestStorage(t)
+	userStore := mocks.NewUserStorage(t)
+	teamStore := mocks.NewTeamStorage(t)
+
+	broken := domain.PullRequest{ID: "pr1", AuthorID: "gone", Status: domain.PRStatusOpen}
+	lonely := domain.PullRequest{ID: "pr2", AuthorID: "u1", Status: domain.PRStatusOpen}
+
+	prStore.
+		On("ListUnderstaffed", ctx, backfillBatchSize).
+		Return([]domain.PullRequest{broken, lonely}, nil).Once()
+
+	prStore.
+		On("MarkBackfillAttempted", ctx, []string{"pr1", "pr2"}).
+		Return(nil).Once()
+
+	prStore.
+		On("GetPullRequestByIDForUpdate", ctx, "pr1").
+		Return(broken, nil).Once()
+
+	userStore.
+		On("GetUserByID", ctx, "gone").
+		Return(nil, domain.ErrNotFound).Once()
+
+	prStore.
+		On("GetPullRequestByIDForUpdate", ctx, "pr2").
+		Return(lonely, nil).Once()
+
+	userStore.
+		On("GetUserByID", ctx, "u1").
+		Return(&domain.User{ID: "u1", TeamName: "team-A", IsActive: true}, nil).Once()
+
+	teamStore.
+		On("GetTeamSettings", ctx, "team-A").
+		Return(defaultSettings, nil).Once()
+
+	userStore.
+		On("ListActiveUserByTeam", ctx, "team-A").
+		Return([]domain.User{
+			{ID: "u1", TeamName: "team-A", IsActive: true},
+			{ID: "u2", TeamName: "team-A", IsActive: true},
+		}, nil).Once()
+
+	prStore.
+		On("AddReviewer", ctx, "pr2", "u2", false, false).
+		Return(nil).Once()
+
+	prStore.
+		On("AddAssignmentAudits", ctx, mock.Anything).
+		Return(nil).Once()
+
+	svc := NewService(teamStore, userStore, prStore, &mockTxManager{}, NewRandomSelector(), nil)
+
+	got, err := svc.BackfillReviewers(ctx, "")
+	require.NoError(t, err)
+	require.Len(t, got, 2)
+	assert.Equal(t, "pr1", got[0].PullRequest.ID)
+	require.ErrorIs(t, got[0].Err, domain.ErrNotFound)
+	assert.Equal(t, "pr2", got[1].PullRequest.ID)
+	assert.NoError(t, got[1].Err)
+	assert.Equal(t, []string{"u2"}, got[1].AddedReviewers)
+}
+
+func TestService_BackfillReviewers_RotatesUnstaffable(t *testing.T) {
+	ctx := context.Background()
+
+	prStore := mocks.NewPullRequestStorage(t)
+	userStore := mocks.NewUserStorage(t)
+	teamStore := mocks.NewTeamStorage(t)
+
+	// the author is alone in the team, no PR can be staffed
+	prs := make([]domain.PullRequest, backfillBatchSize+1)
+	for i := range prs {
+		prs[i] = domain.PullRequest{ID: fmt.Sprintf("pr%03d", i), AuthorID: "u1", Status: domain.PRStatusNeedsReviewers}
+	}
+	attempts := make(map[string]int)
+	batch := 0
+
+	prStore.
+		On("ListUnderstaffed", ctx, backfillBatchSize).
+		Return(func(_ context.Context, limit int) []domain.PullRequest {
+			sorted := slices.Clone(prs)
+			slices.SortStableFunc(sorted, func(a, b domain.PullRequest) int {
+				return cmp.Compare(attempts[a.ID], attempts[b.ID])
+			})
+			return sorted[:limit]
+		}, nil).Twice()
+
+	prStore.
+		On("MarkBackfillAttempted", ctx, mock.Anything).
+		Return(func(_ context.Context, ids []string) error {
+			batch++
+			for _, id := range ids {
+				attempts[id] = batch
+			}
+			return nil
+		}).Twice()
+
+	prStore.
+		On("GetPullRequestByIDForUpdate", ctx, mock.Anything).
+		Return(func(_ context.Context, id string) domain.PullRequest {
+			return domain.PullRequest{ID: id, AuthorID: "u1", Status: domain.PRStatusNeedsReviewers}
+		}, nil)
+
+	userStore.
+		On("GetUserByID", ctx, "u1").
+		Return(&domain.User{ID: "u1", TeamName: "team-A", IsActive: true}, nil)
+
+	teamStore.
+		On("GetTeamSettings", ctx, "team-A").
+		Return(defaultSettings, nil)
+
+	userStore.
+		On("ListActiveUserByTeam", ctx, "team-A").
+		Return([]domain.User{{ID: "u1", TeamName: "team-A", IsActive: true}}, nil)
+
+	svc := NewService(teamStore, userStore, prStore, &mockTxManager{}, NewRandomSelector(), nil)
+
+	for range 2 {
+		_, err := svc.BackfillReviewers(ctx, "")
+		require.NoError(t, err)
+	}
+
+	last := prs[len(prs)-1].ID
+	assert.Equal(t, 2, attempts[last], "the PR past the first batch is tried in the second one")
+	assert.Equal(t, 2, attempts[prs[0].ID], "tried PRs go round again once all were tried")
+}
+
+func TestService_BackfillReviewers_KeepsSeniorityPolicy(t *testing.T) {
+	ctx := context.Background()
+
+	prStore := mocks.NewPullRequestStorage(t)
+	userStore := mocks.NewUserStorage(t)
+	teamStore := mocks.NewTeamStorage(t)
+
+	settings := defaultSettings
+	settings.RequireSenior = true
+
+	prStore.
+		On("GetPullRequestByIDForUpdate", ctx, "pr1").
+		Return(domain.PullRequest{ID: "pr1", AuthorID: "author", Status: domain.PRStatusOpen, AssignedReviewers: []string{"j1"}}, nil).Once()
+
+	userStore.
+		On("GetUserByID", ctx, "author").
+		Return(&domain.User{ID: "author", TeamName: "team-A"}, nil).Once()
+
+	teamStore.
+		On("GetTeamSettings", ctx, "team-A").
+		Return(settings, nil).Once()
+
+	userStore.
+		On("GetUserByID", ctx, "j1").
+		Return(&domain.User{ID: "j1", TeamName: "team-A", IsActive: true, Seniority: domain.SeniorityJunior}, nil).Once()
+
+	userStore.
+		On("ListActiveUserByTeam", ctx, "team-A").
+		Return([]domain.User{
+			{ID: "j1", TeamName: "team-A", IsActive: true, Seniority: domain.SeniorityJunior},
+			{ID: "m1", TeamName: "team-A", IsActive: true, Seniority: domain.SeniorityMiddle},
+			{ID: "s1", TeamName: "team-A", IsActive: true, Seniority: domain.SenioritySenior},
+		}, nil).Once()
+
+	prStore.
+		On("AddReviewer", ctx, "pr1", "s1", false, false).
+		Return(nil).Once()
+
+	prStore.
+		On("AddAssignmentAudits", ctx, mock.Anything).
+		Return(nil).Once()
+
+	svc := NewService(teamStore, userStore, prStore, &mockTxManager{}, NewRandomSelector(), nil)
+
+	got, err := svc.BackfillReviewers(ctx, "pr1")
+	require.NoError(t, err)
+	require.Len(t, got, 1)
+	assert.Equal(t, []string{"s1"}, got[0].AddedReviewers)
+}
+
+func TestService_BackfillReviewers_AssignsMandatory(t *testing.T) {
+	ctx := context.Background()
+
+	prStore := mocks.NewPullRequestStorage(t)
+	userStore := mocks.NewUserStorage(t)
+	teamStore := mocks.NewTeamStorage(t)
+
+	settings := defaultSettings
+	settings.MandatoryReviewers = []string{"lead"}
+
+	prStore.
+		On("GetPullRequestByIDForUpdate", ctx, "pr1").
+		Return(domain.PullRequest{ID: "pr1", AuthorID: "author", Status: domain.PRStatusOpen, AssignedReviewers: []string{"u1"}}, nil).Once()
+
+	userStore.
+		On("GetUserByID", ctx, "author").
+		Return(&domain.User{ID: "author", TeamName: "team-A"}, nil).Once()
+
+	teamStore.
+		On("GetTeamSettings", ctx, "team-A").
+		Return(settings, nil).Once()
+
+	userStore.
+		On("GetUserByID", ctx, "lead").
+		Return(&domain.User{ID: "lead", TeamName: "team-A", IsActive: true}, nil).Once()
+
+	userStore.
+		On("ListActiveUserByTeam", ctx, "team-A").
+		Return([]domain.User{
+			{ID: "u1", TeamName: "team-A", IsActive: true},
+			{ID: "u2", TeamName: "team-A", IsActive: true},
+			{ID: "lead", TeamName: "team-A", IsActive: true},
+		}, nil).Once()
+
+	prStore.
+		On("AddReviewer", ctx, "pr1", "lead", false, true).
+		Return(nil).Once()
+
+	prStore.
+		On("AddAssignmentAudits", ctx, mock.Anything).
+		Return(nil).Once()
+
+	svc := NewService(teamStore, userStore, prStore, &mockTxManager{}, NewRandomSelector(), nil)
+
+	got, err := svc.BackfillReviewers(ctx, "pr1")
+	require.NoError(t, err)
+	require.Len(t, got, 1)
+	assert.Equal(t, []string{"lead"}, got[0].AddedReviewers)
+	assert.Equal(t, []string{"lead"}, got[0].PullRequest.MandatoryReviewers)
+}
+
+func TestService_BackfillReviewers_MergedPR(t *testing.T) {
+	ctx := context.Background()
+
+	prStore := mocks.NewPullRequestStorage(t)
+
+	prStore.
+		On("GetPullRequestByIDForUpdate", ctx, "pr1").
+		Return(domain.PullRequest{ID: "pr1", Status: domain.PRStatusMerged}, nil).Once()
+
+	svc := NewService(mocks.NewTeamStorage(t), mocks.NewUserStorage(t), prStore, &mockTxManager{}, NewRandomSelector(), nil)
+
+	_, err := svc.BackfillReviewers(ctx, "pr1")
+	require.ErrorIs(t, err, domain.ErrPRMerged)
+}
+
+func TestService_ReassignReviewer_Success(t *testing.T) {
+	ctx := context.Background()
+
+	prStore := mocks.NewPullRequestStorage(t)
+	userStore := mocks.NewUserStorage(t)
+	teamStore := mocks.NewTeamStorage(t)
+	tx := &mockTxManager{}
+
+	pr := domain.PullRequest{
+		ID:                "pr1",
+		Status:            domain.PRStatusOpen,
+		AuthorID:          "author",
+		AssignedReviewers: []string{"r1", "r2"},
+	}
+
+	oldUser := &domain.User{
+		ID:       "r1",
+		TeamName: "team-A",
+		IsActive: true,
+	}
+
+	candidates := []domain.User{
+		{ID: "r1", TeamName: "team-A", IsActive: true},
+		{ID: "r2", TeamName: "team-A", IsActive: true},
+		{ID: "r3", TeamName: "team-A", IsActive: true},
+	}
+
+	prStore.
+		On("GetPullRequestByIDForUpdate", ctx, "pr1").
+		Return(pr, nil).Once()
+
+	userStore.
+		On("GetUserByID", ctx, "r1").
+		Return(oldUser, nil).Once()
+
+	teamStore.
+		On("GetTeamSettings", ctx, "team-A").
+		Return(defaultSettings, nil).Once()
+
+	userStore.
+		On("ListActiveUserByTeam", ctx, "team-A").
+		Return(candidates, nil).Once()
+
+	prStore.
+		On("ReplaceReviewer", ctx, "pr1", "r1", "r3", false).
+		Return(nil).Once()
+
+	prStore.
+		On("AddAssignmentAudits", ctx, mock.Anything).
+		Return(nil).Once()
+
+	expectDecision(ctx, teamStore, prStore)
+
+	svc := NewService(teamStore, userStore, prStore, tx, NewRandomSelector(), nil)
+
+	gotPR, replacedBy, err := svc.ReassignReviewer(ctx, "pr1", "r1", false)
+	require.NoError(t, err)
+	assert.Equal(t, "r3", replacedBy)
+	assert.Equal(t, "pr1", gotPR.ID)
+	assert.Equal(t, domain.PRStatusOpen, gotPR.Status)
+	assert.Contains(t, gotPR.AssignedReviewers, "r3")
+	assert.Contains(t, gotPR.AssignedReviewers, "r2")
+	assert.NotContains(t, gotPR.AssignedReviewers, "r1")
+
+	prStore.AssertExpectations(t)
+	userStore.AssertExpectations(t)
+}
+
+func TestService_ReassignReviewer_NoCandidate(t *testing.T) {
+	ctx := context.Background()
+
+	prStore := mocks.NewPullRequestStorage(t)
+	userStore := mocks.NewUserStorage(t)
+	teamStore := mocks.NewTeamStorage(t)
+	tx := &mockTxManager{}
+
+	pr := domain.PullRequest{
+		ID:                "pr1",
+		Status:            domain.PRStatusOpen,
+		AssignedReviewers: []string{"r1"},
+	}
+
+	oldUser := &domain.User{
+		ID:       "r1",
+		TeamName: "team-A",
+		IsActive: true,
+	}
+
+	prStore.
+		On("GetPullRequestByIDForUpdate", ctx, "pr1").
+		Return(pr, nil).Once()
+
+	userStore.
+		On("GetUserByID", ctx, "r1").
+		Return(oldUser, nil).Once()
+
+	teamStore.
+		On("GetTeamSettings", ctx, "team-A").
+		Return(defaultSettings, nil).Once()
+
+	userStore.
+		On("ListActiveUserByTeam", ctx, "team-A").
+		Return([]domain.User{{ID: "r1", TeamName: "team-A", IsActive: true}}, nil).Once()
+
+	svc := NewService(teamStore, userStore, prStore, tx, NewRandomSelector(), nil)
+
+	_, _, err := svc.ReassignReviewer(ctx, "pr1", "r1", false)
+	require.Error(t, err)
+	assert.True(t, errors.Is(err, domain.ErrNoCandidate))
+
+	prStore.AssertExpectations(t)
+	userStore.AssertExpectations(t)
+}
+
+func TestService_DeactivateUser_ReassignsOpenReviews(t *testing.T) {
+	ctx := context.Background()
+
+	prStore := mocks.NewPullRequestStorage(t)
+	userStore := mocks.NewUserStorage(t)
+	teamStore := mocks.NewTeamStorage(t)
+	tx := &mockTxManager{}
+
+	leaving := &domain.User{ID: "r1", TeamName: "team-A", IsActive: true}
+
+	prs := []domain.PullRequest{
+		{ID: "pr1", AuthorID: "u1", Status: domain.PRStatusOpen, AssignedReviewers: []string{"r1"}},
+		{ID: "pr2", AuthorID: "r2", Status: domain.PRStatusOpen, AssignedReviewers: []string{"r1"}},
+	}
+
+	userStore.
+		On("GetUserByID", ctx, "r1").
+		Return(leaving, nil)
+
+	userStore.
+		On("SetIsActive", ctx, "r1", false).
+		Return(nil).Once()
+
+	prStore.
+		On("ListOpenByReviewersForUpdate", ctx, []string{"r1"}).
+		Return(prs, nil).Once()
+
+	teamStore.
+		On("GetTeamSettings", ctx, "team-A").
+		Return(defaultSettings, nil)
+
+	userStore.
+		On("ListActiveUserByTeam", ctx, "team-A").
+		Return([]domain.User{
+			{ID: "u1", TeamName: "team-A", IsActive: true},
+			{ID: "r2", TeamName: "team-A", IsActive: true},
+		}, nil)
+
+	prStore.
+		On("ReplaceReviewers", ctx, []domain.Reassignment{
+			{PullRequestID: "pr1", OldReviewerID: "r1", NewReviewerID: "r2"},
+			{PullRequestID: "pr2", OldReviewerID: "r1", NewReviewerID: "u1"},
+		}).
+		Return(nil).Once()
+
+	prStore.
+		On("AddAssignmentAudits", ctx, mock.Anything).
+		Return(nil).Once()
+
+	svc := NewService(teamStore, userStore, prStore, tx, NewRandomSelector(), nil)
+
+	user, got, err := svc.DeactivateUser(ctx, "r1")
+	require.NoError(t, err)
+	assert.False(t, user.IsActive)
+	assert.Equal(t, []domain.Reassignment{
+		{PullRequestID: "pr1", OldReviewerID: "r1", NewReviewerID: "r2"},
+		{PullRequestID: "pr2", OldReviewerID: "r1", NewReviewerID: "u1"},
+	}, got)
+}
+
+func TestService_DeactivateUser_ReportsNoCandidate(t *testing.T) {
+	ctx := context.Background()
+
+	prStore := mocks.NewPullRequestStorage(t)
+	userStore := mocks.NewUserStorage(t)
+	teamStore := mocks.NewTeamStorage(t)
+	tx := &mockTxManager{}
+
+	userStore.
+		On("GetUserByID", ctx, "r1").
+		Return(&domain.User{ID: "r1", TeamName: "team-A", IsActive: true}, nil)
+
+	userStore.
+		On("SetIsActive", ctx, "r1", false).
+		Return(nil).Once()
+
+	prStore.
+		On("ListOpenByReviewersForUpdate", ctx, []string{"r1"}).
+		Return([]domain.PullRequest{
+			{ID: "pr1", AuthorID: "u1", Status: domain.PRStatusOpen, AssignedReviewers: []string{"r1"}},
+		}, nil).Once()
+
+	teamStore.
+		On("GetTeamSettings", ctx, "team-A").
+		Return(defaultSettings, nil).Once()
+
+	userStore.
+		On("ListActiveUserByTeam", ctx, "team-A").
+		Return([]domain.User{{ID: "u1", TeamName: "team-A", IsActive: true}}, nil).Once()
+
+	svc := NewService(teamStore, userStore, prStore, tx, NewRandomSelector(), nil)
+
+	_, got, err := svc.DeactivateUser(ctx, "r1")
+	require.NoError(t, err)
+	assert.Equal(t, []domain.Reassignment{
+		{PullRequestID: "pr1", OldReviewerID: "r1"},
+	}, got)
+}
+
+func TestService_DeactivateTeamUsers(t *testing.T) {
+	ctx := context.Background()
+
+	prStore := mocks.NewPullRequestStorage(t)
+	userStore := mocks.NewUserStorage(t)
+	teamStore := mocks.NewTeamStorage(t)
+	tx := &mockTxManager{}
+
+	team := &domain.Team{
+		Name: "team-A",
+		Members: []domain.User{
+			{ID: "u1", TeamName: "team-A", IsActive: true},
+			{ID: "u2", TeamName: "team-A", IsActive: true},
+			{ID: "r1", TeamName: "team-A", IsActive: true},
+			{ID: "r2", TeamName: "team-A", IsActive: true},
+		},
+	}
+
+	capacity := 1
+	teamStore.
+		On("GetWithMembers", ctx, "team-A").
+		Return(team, nil).Once()
+
+	userStore.
+		On("SetIsActiveMany", ctx, []string{"r1", "r2"}, false).
+		Return(nil).Once()
+
+	prStore.
+		On("ListOpenByReviewersForUpdate", ctx, []string{"r1", "r2"}).
+		Return([]domain.PullRequest{
+			{ID: "pr1", AuthorID: "u1", Status: domain.PRStatusOpen, AssignedReviewers: []string{"r1", "r2"}},
+			{ID: "pr2", AuthorID: "u1", Status: domain.PRStatusOpen, AssignedReviewers: []string{"r2"}},
+		}, nil).Once()
+
+	teamStore.
+		On("GetTeamSettings", ctx, "team-A").
+		Return(defaultSettings, nil).Once()
+
+	userStore.
+		On("ListActiveUserByTeam", ctx, "team-A").
+		Return([]domain.User{
+			{ID: "u1", TeamName: "team-A", IsActive: true},
+			{ID: "u2", TeamName: "team-A", IsActive: true, MaxOpenReviews: &capacity},
+		}, nil).Once()
+
+	prStore.
+		On("CountOpenReviews", ctx, []string{"u2"}).
+		Return(map[string]int{}, nil).Once()
+
+	// u2 is at capacity after pr1, so pr2 keeps r2
+	prStore.
+		On("ReplaceReviewers", ctx, []domain.Reassignment{
+			{PullRequestID: "pr1", OldReviewerID: "r1", NewReviewerID: "u2"},
+		}).
+		Return(nil).Once()
+
+	prStore.
+		On("AddAssignmentAudits", ctx, mock.Anything).
+		Return(nil).Once()
+
+	svc := NewService(teamStore, userStore, prStore, tx, NewRandomSelector(), nil)
+
+	users, got, err := svc.DeactivateTeamUsers(ctx, "team-A", []string{"r1", "r2", "r1"})
+	require.NoError(t, err)
+	require.Len(t, users, 2)
+	assert.False(t, users[0].IsActive)
+	assert.False(t, users[1].IsActive)
+	assert.Equal(t, []domain.Reassignment{
+		{PullRequestID: "pr1", OldReviewerID: "r1", NewReviewerID: "u2"},
+		{PullRequestID: "pr1", OldReviewerID: "r2"},
+		{PullRequestID: "pr2", OldReviewerID: "r2"},
+	}, got)
+}
+
+func TestService_DeactivateTeamUsers_QueriesDoNotGrowWithPullRequests(t *testing.T) {
+	ctx := context.Background()
+
+	prStore := mocks.NewPullRequestStorage(t)
+	userStore := mocks.NewUserStorage(t)
+	teamStore := mocks.NewTeamStorage(t)
+
+	settings := defaultSettings
+	settings.Strategy = domain.StrategyPairDiversity
+	settings.RequireSenior = true
+
+	// every PR has its own author and a junior from another team next to r1,
+	// so r1 is replaced with a senior chosen by pair history of the author
+	const n = 10
+	prs := make([]domain.PullRequest, 0, n)
+	authors := make([]string, 0, n)
+	juniors := make([]domain.User, 0, n)
+	for i := range n {
+		author, junior := fmt.Sprintf("a%d", i), fmt.Sprintf("j%d", i)
+		prs = append(prs, domain.PullRequest{ID: fmt.Sprintf("pr%d", i), AuthorID: author, Status: domain.PRStatusOpen, AssignedReviewers: []string{"r1", junior}})
+		authors = append(authors, author)
+		juniors = append(juniors, domain.User{ID: junior, TeamName: "team-B", IsActive: true, Seniority: domain.SeniorityJunior})
+	}
+
+	teamStore.
+		On("GetWithMembers", ctx, "team-A").
+		Return(&domain.Team{Name: "team-A", Members: []domain.User{{ID: "r1", TeamName: "team-A", IsActive: true}}}, nil).Once()
+
+	userStore.
+		On("SetIsActiveMany", ctx, []string{"r1"}, false).
+		Return(nil).Once()
+
+	prStore.
+		On("ListOpenByReviewersForUpdate", ctx, []string{"r1"}).
+		Return(prs, nil).Once()
+
+	teamStore.
+		On("GetTeamSettings", ctx, "team-A").
+		Return(settings, nil).Once()
+
+	userStore.
+		On("ListActiveUserByTeam", ctx, "team-A").
+		Return([]domain.User{
+			{ID: "s1", TeamName: "team-A", IsActive: true, Seniority: domain.SenioritySenior},
+			{ID: "s2", TeamName: "team-A", IsActive: true, Seniority: domain.SeniorityLead},
+		}, nil).Once()
+
+	userStore.
+		On("GetUsersByIDs", ctx, mock.Anything).
+		Return(juniors, nil).Once()
+
+	prStore.
+		On("RecentReviewersByAuthors", ctx, authors, 20).
+		Return(map[string][]string{"a0": {"s1"}}, nil).Once()
+
+	prStore.
+		On("ReplaceReviewers", ctx, mock.Anything).
+		Return(nil).Once()
+
+	prStore.
+		On("AddAssignmentAudits", ctx, mock.Anything).
+		Return(nil).Once()
+
+	selector := NewStrategySelector(NewRandomSelector(), map[domain.SelectionStrategy]ReviewerSelector{
+		domain.StrategyPairDiversity: NewPairDiversitySelector(prStore, 20),
+	})
+	svc := NewService(teamStore, userStore, prStore, &mockTxManager{}, selector, nil)
+
+	_, got, err := svc.DeactivateTeamUsers(ctx, "team-A", []string{"r1"})
+	require.NoError(t, err)
+	require.Len(t, got, n)
+	for _, reassignment := range got {
+		assert.Contains(t, []string{"s1", "s2"}, reassignment.NewReviewerID)
+	}
+
+	// the same calls for any number of PRs, no GetUserByID or RecentReviewers per PR
+	assert.Len(t, prStore.Calls, 4)
+	assert.Len(t, userStore.Calls, 3)
+	assert.Len(t, teamStore.Calls, 2)
+}
+
+func TestService_DeactivateTeamUsers_UnknownMember(t *testing.T) {
+	ctx := context.Background()
+
+	teamStore := mocks.NewTeamStorage(t)
+	teamStore.
+		On("GetWithMembers", ctx, "team-A").
+		Return(&domain.Team{Name: "team-A", Members: []domain.User{{ID: "u1", TeamName: "team-A"}}}, nil).Once()
+
+	svc := NewService(teamStore, mocks.NewUserStorage(t), mocks.NewPullRequestStorage(t), &mockTxManager{}, NewRandomSelector(), nil)
+
+	_, _, err := svc.DeactivateTeamUsers(ctx, "team-A", []string{"u1", "u42"})
+	require.ErrorIs(t, err, domain.ErrNotFound)
+}
+
+func TestService_CreatePullRequest_AssignsUpToTwoReviewers(t *testing.T) {
+	ctx := context.Background()
+
+	type testCase struct {
+		name             string
+		activeUsers      []domain.User
+		wantReviewersLen int
+	}
+
+	tests := []testCase{
+		{
+			name:             "no_candidates_assigns_zero",
+			activeUsers:      []domain.User{},
+			wantReviewersLen: 0,
+		},
+		{
+			name: "one_candidate_assigns_one",
+			activeUsers: []domain.User{
+				{ID: "u2", TeamName: "team-A", IsActive: true},
+			},
+			wantReviewersLen: 1,
+		},
+		{
+			name: "three_candidates_assigns_two",
+			activeUsers: []domain.User{
+				{ID: "u2", TeamName: "team-A", IsActive: true},
+				{ID: "u3", TeamName: "team-A", IsActive: true},
+				{ID: "u4", TeamName: "team-A", IsActive: true},
+			},
+			wantReviewersLen: 2,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			prStore := mocks.NewPullRequestStorage(t)
+			userStore := mocks.NewUserStorage(t)
+			teamStore := mocks.NewTeamStorage(t)
+			tx := &mockTxManager{}
+
+			author := &domain.User{
+				ID:       "u1",
+				TeamName: "team-A",
+				IsActive: true,
+			}
+
+			prStore.
+				On("GetPullRequestByID", ctx, "pr-1").
+				Return(domain.PullRequest{}, domain.ErrNotFound).
+				Once()
+
+			userStore.
+				On("GetUserByID", ctx, "u1").
+				Return(author, nil).
+				Once()
+
+			teamStore.
+				On("GetTeamSettings", ctx, "team-A").
+				Return(defaultSettings, nil).
+				Once()
+
+			users := append([]domain.User{*author}, tt.activeUsers...)
+			userStore.
+				On("ListActiveUserByTeam", ctx, "team-A").
+				Return(users, nil).
+				Once()
+
+			prStore.
+				On("Create", ctx, mock.MatchedBy(func(pr domain.PullRequest) bool {
+					if pr.ID != "pr-1" || pr.AuthorID != "u1" {
+						return false
+					}
+					if pr.Status != domain.PRStatusOpen {
+						return false
+					}
+					if len(pr.AssignedReviewers) != tt.wantReviewersLen {
+						return false
+					}
+					for _, id := range pr.AssignedReviewers {
+						if id == "u1" {
+							return false
+						}
+					}
+					return true
+				})).
+				Return(nil).
+				Once()
+
+			prStore.
+				On("AddAssignmentAudits", ctx, mock.Anything).
+				Return(nil).Once()
+
+			expectDecision(ctx, teamStore, prStore)
+
+			svc := NewService(teamStore, userStore, prStore, tx, NewRandomSelector(), nil)
+
+			got, err := svc.CreatePullRequest(ctx, domain.PullRequestCreate{ID: "pr-1", Name: "PR name", AuthorID: "u1"})
+			require.NoError(t, err)
+			assert.Equal(t, "pr-1", got.ID)
+			assert.Equal(t, "u1", got.AuthorID)
+			assert.Len(t, got.AssignedReviewers, tt.wantReviewersLen)
+
+			prStore.AssertExpectations(t)
+			userStore.AssertExpectations(t)
+		})
+	}
+}
+
+func TestService_CreatePullRequest_UsesTeamSettings(t *testing.T) {
+	ctx := context.Background()
+
+	activeUsers := []domain.User{
+		{ID: "u1", TeamName: "team-A", IsActive: true},
+		{ID: "u2", TeamName: "team-A", IsActive: true},
+		{ID: "u3", TeamName: "team-A", IsActive: true},
+		{ID: "u4", TeamName: "team-A", IsActive: true},
+	}
+
+	tests := []struct {
+		name             string
+		settings         domain.TeamSettings
+		activeUsers      []domain.User
+		wantReviewersLen int
+		wantErr          error
+	}{
+		{
+			name:             "max_reviewers_three",
+			settings:         domain.TeamSettings{TeamName: "team-A", MinReviewers: 1, MaxReviewers: 3, AllowPartial: true},
+			activeUsers:      activeUsers,
+			wantReviewersLen: 3,
+		},
+		{
+			name:             "partial_allowed",
+			settings:         domain.TeamSettings{TeamName: "team-A", MinReviewers: 2, MaxReviewers: 2, AllowPartial: true},
+			activeUsers:      activeUsers[:2],
+			wantReviewersLen: 1,
+		},
+		{
+			name:        "partial_forbidden",
+			settings:    domain.TeamSettings{TeamName: "team-A", MinReviewers: 2, MaxReviewers: 2, AllowPartial: false},
+			activeUsers: activeUsers[:2],
+			wantErr:     domain.ErrNoCandidate,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			prStore := mocks.NewPullRequestStorage(t)
+			userStore := mocks.NewUserStorage(t)
+			teamStore := mocks.NewTeamStorage(t)
+			tx := &mockTxManager{}
+
+			prStore.
+				On("GetPullRequestByID", ctx, "pr-1").
+				Return(domain.PullRequest{}, domain.ErrNotFound).Once()
+
+			userStore.
+				On("GetUserByID", ctx, "u1").
+				Return(&activeUsers[0], nil).Once()
+
+			teamStore.
+				On("GetTeamSettings", ctx, "team-A").
+				Return(tt.settings, nil).Once()
+
+			userStore.
+				On("ListActiveUserByTeam", ctx, "team-A").
+				Return(tt.activeUsers, nil).Once()
+
+			if tt.wantErr == nil {
+				prStore.
+					On("Create", ctx, mock.AnythingOfType("domain.PullRequest")).
+					Return(nil).Once()
+
+				prStore.
+					On("AddAssignmentAudits", ctx, mock.Anything).
+					Return(nil).Once()
+
+				expectDecision(ctx, teamStore, prStore)
+			}
+
+			svc := NewService(teamStore, userStore, prStore, tx, NewRandomSelector(), nil)
+
+			got, err := svc.CreatePullRequest(ctx, domain.PullRequestCreate{ID: "pr-1", Name: "PR name", AuthorID: "u1"})
+			if tt.wantErr != nil {
+				require.ErrorIs(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Len(t, got.AssignedReviewers, tt.wantReviewersLen)
+			assert.NotContains(t, got.AssignedReviewers, "u1")
+		})
+	}
+}
+
+func TestService_CreatePullRequest_FallsBackToBackupTeams(t *testing.T) {
+	ctx := context.Background()
+
+	prStore := mocks.NewPullRequestStorage(t)
+	userStore := mocks.NewUserStorage(t)
+	teamStore := mocks.NewTeamStorage(t)
+	tx := &mockTxManager{}
+
+	author := &domain.User{ID: "u1", TeamName: "team-A", IsActive: true}
+
+	settings := defaultSettings
+	settings.BackupTeams = []string{"team-B", "team-C"}
+
+	prStore.
+		On("GetPullRequestByID", ctx, "pr-1").
+		Return(domain.PullRequest{}, domain.ErrNotFound).Once()
+
+	userStore.
+		On("GetUserByID", ctx, "u1").
+		Return(author, nil).Once()
+
+	teamStore.
+		On("GetTeamSettings", ctx, "team-A").
+		Return(settings, nil).Once()
+
+	userStore.
+		On("ListActiveUserByTeam", ctx, "team-A").
+		Return([]domain.User{*author}, nil).Once()
+
+	userStore.
+		On("ListActiveUserByTeam", ctx, "team-B").
+		Return([]domain.User{{ID: "b1", TeamName: "team-B", IsActive: true}}, nil).Once()
+
+	userStore.
+		On("ListActiveUserByTeam", ctx, "team-C").
+		Return([]domain.User{{ID: "c1", TeamName: "team-C", IsActive: true}}, nil).Once()
+
+	prStore.
+		On("Create", ctx, mock.AnythingOfType("domain.PullRequest")).
+		Return(nil).Once()
+
+	prStore.
+		On("AddAssignmentAudits", ctx, mock.Anything).
+		Return(nil).Once()
+
+	expectDecision(ctx, teamStore, prStore)
+
+	svc := NewService(teamStore, userStore, prStore, tx, NewRandomSelector(), nil)
+
+	got, err := svc.CreatePullRequest(ctx, domain.PullRequestCreate{ID: "pr-1", Name: "PR name", AuthorID: "u1"})
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []string{"b1", "c1"}, got.AssignedReviewers)
+	assert.ElementsMatch(t, []string{"b1", "c1"}, got.FallbackReviewers)
 }
 
-func TestService_MergePullRequest_Idempotent(t *testing.T) {
+func TestService_ReassignReviewer_FallsBackToBackupTeam(t *testing.T) {
 	ctx := context.Background()
 
 	prStore := mocks.NewPullRequestStorage(t)
@@ -27,40 +1215,55 @@ func TestService_MergePullRequest_Idempotent(t *testing.T) {
 	tx := &mockTxManager{}
 
 	pr := domain.PullRequest{
-		ID:       "pr1",
-		Status:   domain.PRStatusOpen,
-		MergedAt: nil,
+		ID:                "pr1",
+		Status:            domain.PRStatusOpen,
+		AuthorID:          "author",
+		AssignedReviewers: []string{"r1"},
 	}
 
+	settings := defaultSettings
+	settings.BackupTeams = []string{"team-B"}
+
 	prStore.
 		On("GetPullRequestByIDForUpdate", ctx, "pr1").
 		Return(pr, nil).Once()
 
-	prStore.
-		On("UpdateStatusMerged", ctx, "pr1", mock.AnythingOfType("*time.Time")).
-		Return(nil).Once()
+	userStore.
+		On("GetUserByID", ctx, "r1").
+		Return(&domain.User{ID: "r1", TeamName: "team-A", IsActive: true}, nil).Once()
 
-	svc := NewService(teamStore, userStore, prStore, tx)
+	teamStore.
+		On("GetTeamSettings", ctx, "team-A").
+		Return(settings, nil).Once()
 
-	got1, err1 := svc.MergePullRequest(ctx, "pr1")
-	require.NoError(t, err1)
-	assert.Equal(t, domain.PRStatusMerged, got1.Status)
-	assert.NotNil(t, got1.MergedAt)
+	userStore.
+		On("ListActiveUserByTeam", ctx, "team-A").
+		Return([]domain.User{{ID: "r1", TeamName: "team-A", IsActive: true}}, nil).Once()
+
+	userStore.
+		On("ListActiveUserByTeam", ctx, "team-B").
+		Return([]domain.User{{ID: "b1", TeamName: "team-B", IsActive: true}}, nil).Once()
 
-	mergedPR := got1
 	prStore.
-		On("GetPullRequestByIDForUpdate", ctx, "pr1").
-		Return(mergedPR, nil).Once()
+		On("ReplaceReviewer", ctx, "pr1", "r1", "b1", true).
+		Return(nil).Once()
 
-	got2, err2 := svc.MergePullRequest(ctx, "pr1")
-	require.NoError(t, err2)
-	assert.Equal(t, domain.PRStatusMerged, got2.Status)
-	assert.Equal(t, got1.MergedAt, got2.MergedAt)
+	prStore.
+		On("AddAssignmentAudits", ctx, mock.Anything).
+		Return(nil).Once()
 
-	prStore.AssertExpectations(t)
+	expectDecision(ctx, teamStore, prStore)
+
+	svc := NewService(teamStore, userStore, prStore, tx, NewRandomSelector(), nil)
+
+	gotPR, replacedBy, err := svc.ReassignReviewer(ctx, "pr1", "r1", false)
+	require.NoError(t, err)
+	assert.Equal(t, "b1", replacedBy)
+	assert.Equal(t, []string{"b1"}, gotPR.AssignedReviewers)
+	assert.Equal(t, []string{"b1"}, gotPR.FallbackReviewers)
 }
 
-func TestService_ReassignReviewer_Success(t *testing.T) {
+func TestService_CreatePullRequest_NeedsReviewersWhenAtCapacity(t *testing.T) {
 	ctx := context.Background()
 
 	prStore := mocks.NewPullRequestStorage(t)
@@ -68,57 +1271,131 @@ func TestService_ReassignReviewer_Success(t *testing.T) {
 	teamStore := mocks.NewTeamStorage(t)
 	tx := &mockTxManager{}
 
-	pr := domain.PullRequest{
-		ID:                "pr1",
-		Status:            domain.PRStatusOpen,
-		AuthorID:          "author",
-		AssignedReviewers: []string{"r1", "r2"},
+	capacity := 2
+	author := &domain.User{ID: "u1", TeamName: "team-A", IsActive: true}
+	candidates := []domain.User{
+		*author,
+		{ID: "u2", TeamName: "team-A", IsActive: true, MaxOpenReviews: &capacity},
+		{ID: "u3", TeamName: "team-A", IsActive: true, MaxOpenReviews: &capacity},
 	}
 
-	oldUser := &domain.User{
-		ID:       "r1",
-		TeamName: "team-A",
-		IsActive: true,
-	}
+	settings := defaultSettings
+	settings.AllowPartial = false
+
+	prStore.
+		On("GetPullRequestByID", ctx, "pr-1").
+		Return(domain.PullRequest{}, domain.ErrNotFound).Once()
+
+	userStore.
+		On("GetUserByID", ctx, "u1").
+		Return(author, nil).Once()
+
+	teamStore.
+		On("GetTeamSettings", ctx, "team-A").
+		Return(settings, nil).Once()
+
+	userStore.
+		On("ListActiveUserByTeam", ctx, "team-A").
+		Return(candidates, nil).Once()
+
+	prStore.
+		On("CountOpenReviews", ctx, []string{"u2", "u3"}).
+		Return(map[string]int{"u2": 2, "u3": 5}, nil).Once()
 
+	prStore.
+		On("Create", ctx, mock.MatchedBy(func(pr domain.PullRequest) bool {
+			return pr.Status == domain.PRStatusNeedsReviewers && len(pr.AssignedReviewers) == 0
+		})).
+		Return(nil).Once()
+
+	prStore.
+		On("AddAssignmentAudits", ctx, mock.Anything).
+		Return(nil).Once()
+
+	expectDecision(ctx, teamStore, prStore)
+
+	svc := NewService(teamStore, userStore, prStore, tx, NewRandomSelector(), nil)
+
+	got, err := svc.CreatePullRequest(ctx, domain.PullRequestCreate{ID: "pr-1", Name: "PR name", AuthorID: "u1"})
+	require.NoError(t, err)
+	assert.Equal(t, domain.PRStatusNeedsReviewers, got.Status)
+	assert.Empty(t, got.AssignedReviewers)
+}
+
+func TestService_CreatePullRequest_PrefersWorkingHours(t *testing.T) {
+	ctx := context.Background()
+
+	prStore := mocks.NewPullRequestStorage(t)
+	userStore := mocks.NewUserStorage(t)
+	teamStore := mocks.NewTeamStorage(t)
+	tx := &mockTxManager{}
+
+	now := time.Now().UTC()
+	minute := now.Hour()*60 + now.Minute()
+	working := &domain.WorkingHours{Start: (minute + 1380) % 1440, End: (minute + 60) % 1440}
+	sleeping := &domain.WorkingHours{Start: (minute + 120) % 1440, End: (minute + 180) % 1440}
+
+	author := &domain.User{ID: "u1", TeamName: "team-A", IsActive: true}
 	candidates := []domain.User{
-		{ID: "r1", TeamName: "team-A", IsActive: true},
-		{ID: "r2", TeamName: "team-A", IsActive: true},
-		{ID: "r3", TeamName: "team-A", IsActive: true},
+		*author,
+		{ID: "u2", TeamName: "team-A", IsActive: true, Timezone: "UTC", WorkingHours: sleeping},
+		{ID: "u3", TeamName: "team-A", IsActive: true, Timezone: "UTC", WorkingHours: working},
+		{ID: "u4", TeamName: "team-A", IsActive: true, Timezone: "UTC", WorkingHours: sleeping},
 	}
 
+	settings := defaultSettings
+	settings.PreferWorkingHours = true
+
 	prStore.
-		On("GetPullRequestByIDForUpdate", ctx, "pr1").
-		Return(pr, nil).Once()
+		On("GetPullRequestByID", ctx, "pr-1").
+		Return(domain.PullRequest{}, domain.ErrNotFound).Once()
 
 	userStore.
-		On("GetUserByID", ctx, "r1").
-		Return(oldUser, nil).Once()
+		On("GetUserByID", ctx, "u1").
+		Return(author, nil).Once()
+
+	teamStore.
+		On("GetTeamSettings", ctx, "team-A").
+		Return(settings, nil).Once()
 
 	userStore.
 		On("ListActiveUserByTeam", ctx, "team-A").
 		Return(candidates, nil).Once()
 
 	prStore.
-		On("ReplaceReviewer", ctx, "pr1", "r1", "r3").
+		On("Create", ctx, mock.AnythingOfType("domain.PullRequest")).
 		Return(nil).Once()
 
-	svc := NewService(teamStore, userStore, prStore, tx)
+	prStore.
+		On("AddAssignmentAudits", ctx, mock.Anything).
+		Return(nil).Once()
+
+	expectDecision(ctx, teamStore, prStore)
+
+	svc := NewService(teamStore, userStore, prStore, tx, NewRandomSelector(), nil)
 
-	gotPR, replacedBy, err := svc.ReassignReviewer(ctx, "pr1", "r1")
+	got, err := svc.CreatePullRequest(ctx, domain.PullRequestCreate{ID: "pr-1", Name: "PR name", AuthorID: "u1"})
 	require.NoError(t, err)
-	assert.Equal(t, "r3", replacedBy)
-	assert.Equal(t, "pr1", gotPR.ID)
-	assert.Equal(t, domain.PRStatusOpen, gotPR.Status)
-	assert.Contains(t, gotPR.AssignedReviewers, "r3")
-	assert.Contains(t, gotPR.AssignedReviewers, "r2")
-	assert.NotContains(t, gotPR.AssignedReviewers, "r1")
+	require.Len(t, got.AssignedReviewers, 2)
+	assert.Contains(t, got.AssignedReviewers, "u3")
+}
 
-	prStore.AssertExpectations(t)
-	userStore.AssertExpectations(t)
+func TestService_SetWorkingHours_Validation(t *testing.T) {
+	ctx := context.Background()
+
+	svc := NewService(mocks.NewTeamStorage(t), mocks.NewUserStorage(t), mocks.NewPullRequestStorage(t), &mockTxManager{}, NewRandomSelector(), nil)
+
+	_, err := svc.SetWorkingHours(ctx, "u1", "Mars/Olympus", nil)
+	require.ErrorIs(t, err, domain.ErrInvalidInput)
+
+	_, err = svc.SetWorkingHours(ctx, "u1", "Europe/Moscow", &domain.WorkingHours{Start: 540, End: 540})
+	require.ErrorIs(t, err, domain.ErrInvalidInput)
+
+	_, err = svc.SetWorkingHours(ctx, "u1", "Europe/Moscow", &domain.WorkingHours{Start: 540, End: 1440})
+	require.ErrorIs(t, err, domain.ErrInvalidInput)
 }
 
-func TestService_ReassignReviewer_NoCandidate(t *testing.T) {
+func TestService_CreatePullRequest_AssignsMandatoryReviewers(t *testing.T) {
 	ctx := context.Background()
 
 	prStore := mocks.NewPullRequestStorage(t)
@@ -126,133 +1403,170 @@ func TestService_ReassignReviewer_NoCandidate(t *testing.T) {
 	teamStore := mocks.NewTeamStorage(t)
 	tx := &mockTxManager{}
 
-	pr := domain.PullRequest{
-		ID:                "pr1",
-		Status:            domain.PRStatusOpen,
-		AssignedReviewers: []string{"r1"},
-	}
+	capacity := 0
+	author := &domain.User{ID: "u1", TeamName: "team-A", IsActive: true}
+	lead := &domain.User{ID: "lead", TeamName: "leads", IsActive: true, MaxOpenReviews: &capacity}
 
-	oldUser := &domain.User{
-		ID:       "r1",
-		TeamName: "team-A",
-		IsActive: true,
+	settings := defaultSettings
+	settings.MandatoryReviewers = []string{"u1", "gone", "lead"}
+
+	prStore.
+		On("GetPullRequestByID", ctx, "pr-1").
+		Return(domain.PullRequest{}, domain.ErrNotFound).Once()
+
+	userStore.
+		On("GetUserByID", ctx, "u1").
+		Return(author, nil).Once()
+
+	teamStore.
+		On("GetTeamSettings", ctx, "team-A").
+		Return(settings, nil).Once()
+
+	userStore.
+		On("GetUserByID", ctx, "gone").
+		Return(nil, domain.ErrNotFound).Once()
+
+	userStore.
+		On("GetUserByID", ctx, "lead").
+		Return(lead, nil).Once()
+
+	userStore.
+		On("ListActiveUserByTeam", ctx, "leads").
+		Return([]domain.User{*lead}, nil).Once()
+
+	userStore.
+		On("ListActiveUserByTeam", ctx, "team-A").
+		Return([]domain.User{*author, {ID: "u2", TeamName: "team-A", IsActive: true}}, nil).Once()
+
+	prStore.
+		On("Create", ctx, mock.AnythingOfType("domain.PullRequest")).
+		Return(nil).Once()
+
+	prStore.
+		On("AddAssignmentAudits", ctx, mock.Anything).
+		Return(nil).Once()
+
+	expectDecision(ctx, teamStore, prStore)
+
+	svc := NewService(teamStore, userStore, prStore, tx, NewRandomSelector(), nil)
+
+	got, err := svc.CreatePullRequest(ctx, domain.PullRequestCreate{ID: "pr-1", Name: "PR name", AuthorID: "u1"})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"lead", "u2"}, got.AssignedReviewers)
+	assert.Equal(t, []string{"lead"}, got.MandatoryReviewers)
+}
+
+func TestService_ReassignReviewer_MandatoryNeedsForce(t *testing.T) {
+	ctx := context.Background()
+
+	prStore := mocks.NewPullRequestStorage(t)
+	userStore := mocks.NewUserStorage(t)
+	teamStore := mocks.NewTeamStorage(t)
+	tx := &mockTxManager{}
+
+	pr := domain.PullRequest{
+		ID:                 "pr1",
+		Status:             domain.PRStatusOpen,
+		AuthorID:           "author",
+		AssignedReviewers:  []string{"lead"},
+		MandatoryReviewers: []string{"lead"},
 	}
 
 	prStore.
 		On("GetPullRequestByIDForUpdate", ctx, "pr1").
-		Return(pr, nil).Once()
+		Return(pr, nil).Twice()
+
+	svc := NewService(teamStore, userStore, prStore, tx, NewRandomSelector(), nil)
+
+	_, _, err := svc.ReassignReviewer(ctx, "pr1", "lead", false)
+	require.ErrorIs(t, err, domain.ErrMandatoryReviewer)
 
 	userStore.
-		On("GetUserByID", ctx, "r1").
-		Return(oldUser, nil).Once()
+		On("GetUserByID", ctx, "lead").
+		Return(&domain.User{ID: "lead", TeamName: "team-A", IsActive: true}, nil).Once()
+
+	teamStore.
+		On("GetTeamSettings", ctx, "team-A").
+		Return(defaultSettings, nil).Once()
 
 	userStore.
 		On("ListActiveUserByTeam", ctx, "team-A").
-		Return([]domain.User{{ID: "r1", TeamName: "team-A", IsActive: true}}, nil).Once()
+		Return([]domain.User{{ID: "lead", TeamName: "team-A", IsActive: true}, {ID: "r2", TeamName: "team-A", IsActive: true}}, nil).Once()
 
-	svc := NewService(teamStore, userStore, prStore, tx)
+	prStore.
+		On("ReplaceReviewer", ctx, "pr1", "lead", "r2", false).
+		Return(nil).Once()
 
-	_, _, err := svc.ReassignReviewer(ctx, "pr1", "r1")
-	require.Error(t, err)
-	assert.True(t, errors.Is(err, domain.ErrNoCandidate))
+	prStore.
+		On("AddAssignmentAudits", ctx, mock.Anything).
+		Return(nil).Once()
 
-	prStore.AssertExpectations(t)
-	userStore.AssertExpectations(t)
+	expectDecision(ctx, teamStore, prStore)
+
+	gotPR, replacedBy, err := svc.ReassignReviewer(ctx, "pr1", "lead", true)
+	require.NoError(t, err)
+	assert.Equal(t, "r2", replacedBy)
+	assert.Empty(t, gotPR.MandatoryReviewers)
 }
 
-func TestService_CreatePullRequest_AssignsUpToTwoReviewers(t *testing.T) {
+func TestService_UpdateTeamSettings(t *testing.T) {
 	ctx := context.Background()
 
-	type testCase struct {
-		name             string
-		activeUsers      []domain.User
-		wantReviewersLen int
-	}
+	intPtr := func(v int) *int { return &v }
+	strategyPtr := func(v domain.SelectionStrategy) *domain.SelectionStrategy { return &v }
 
-	tests := []testCase{
+	tests := []struct {
+		name    string
+		update  domain.TeamSettingsUpdate
+		want    domain.TeamSettings
+		wantErr error
+	}{
 		{
-			name:             "no_candidates_assigns_zero",
-			activeUsers:      []domain.User{},
-			wantReviewersLen: 0,
+			name:   "partial_update_keeps_other_fields",
+			update: domain.TeamSettingsUpdate{MaxReviewers: intPtr(3), Strategy: strategyPtr(domain.StrategyLeastLoaded)},
+			want: domain.TeamSettings{
+				TeamName:     "team-A",
+				MinReviewers: 2,
+				MaxReviewers: 3,
+				Strategy:     domain.StrategyLeastLoaded,
+				AllowPartial: true,
+			},
 		},
 		{
-			name: "one_candidate_assigns_one",
-			activeUsers: []domain.User{
-				{ID: "u2", TeamName: "team-A", IsActive: true},
-			},
-			wantReviewersLen: 1,
+			name:    "max_less_than_min",
+			update:  domain.TeamSettingsUpdate{MaxReviewers: intPtr(1)},
+			wantErr: domain.ErrInvalidInput,
 		},
 		{
-			name: "three_candidates_assigns_two",
-			activeUsers: []domain.User{
-				{ID: "u2", TeamName: "team-A", IsActive: true},
-				{ID: "u3", TeamName: "team-A", IsActive: true},
-				{ID: "u4", TeamName: "team-A", IsActive: true},
-			},
-			wantReviewersLen: 2,
+			name:    "unknown_strategy",
+			update:  domain.TeamSettingsUpdate{Strategy: strategyPtr("FASTEST")},
+			wantErr: domain.ErrInvalidInput,
 		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			prStore := mocks.NewPullRequestStorage(t)
-			userStore := mocks.NewUserStorage(t)
 			teamStore := mocks.NewTeamStorage(t)
-			tx := &mockTxManager{}
-
-			author := &domain.User{
-				ID:       "u1",
-				TeamName: "team-A",
-				IsActive: true,
-			}
-
-			prStore.
-				On("GetPullRequestByID", ctx, "pr-1").
-				Return(domain.PullRequest{}, domain.ErrNotFound).
-				Once()
-
-			userStore.
-				On("GetUserByID", ctx, "u1").
-				Return(author, nil).
-				Once()
 
-			users := append([]domain.User{*author}, tt.activeUsers...)
-			userStore.
-				On("ListActiveUserByTeam", ctx, "team-A").
-				Return(users, nil).
-				Once()
+			teamStore.
+				On("GetTeamSettings", ctx, "team-A").
+				Return(defaultSettings, nil).Once()
 
-			prStore.
-				On("Create", ctx, mock.MatchedBy(func(pr domain.PullRequest) bool {
-					if pr.ID != "pr-1" || pr.AuthorID != "u1" {
-						return false
-					}
-					if pr.Status != domain.PRStatusOpen {
-						return false
-					}
-					if len(pr.AssignedReviewers) != tt.wantReviewersLen {
-						return false
-					}
-					for _, id := range pr.AssignedReviewers {
-						if id == "u1" {
-							return false
-						}
-					}
-					return true
-				})).
-				Return(nil).
-				Once()
+			if tt.wantErr == nil {
+				teamStore.
+					On("SaveTeamSettings", ctx, tt.want).
+					Return(nil).Once()
+			}
 
-			svc := NewService(teamStore, userStore, prStore, tx)
+			svc := NewService(teamStore, mocks.NewUserStorage(t), mocks.NewPullRequestStorage(t), &mockTxManager{}, NewRandomSelector(), nil)
 
-			got, err := svc.CreatePullRequest(ctx, "pr-1", "PR name", "u1")
+			got, err := svc.UpdateTeamSettings(ctx, "team-A", tt.update)
+			if tt.wantErr != nil {
+				require.ErrorIs(t, err, tt.wantErr)
+				return
+			}
 			require.NoError(t, err)
-			assert.Equal(t, "pr-1", got.ID)
-			assert.Equal(t, "u1", got.AuthorID)
-			assert.Len(t, got.AssignedReviewers, tt.wantReviewersLen)
-
-			prStore.AssertExpectations(t)
-			userStore.AssertExpectations(t)
+			assert.Equal(t, tt.want, got)
 		})
 	}
 }
@@ -317,7 +1631,7 @@ func Test_chooseReviewers(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			got := chooseReviewers(tt.args.candidates, tt.args.quantity)
+			got := chooseReviewers(rand.New(rand.NewPCG(1, 2)), tt.args.candidates, tt.args.quantity)
 
 			require.Equal(t, tt.wantLen, len(got), "unexpected len")
 
@@ -386,3 +1700,22 @@ func Test_filterUsersExclude(t *testing.T) {
 		})
 	}
 }
+
+// expectDecision expects one assignment decision, every team is described with members of it.
+func expectDecision(ctx context.Context, teamStore *mocks.TeamStorage, prStore *mocks.PullRequestStorage, members ...domain.User) {
+	teamStore.
+		On("GetWithMembers", ctx, mock.Anything).
+		Return(func(_ context.Context, teamName string) (*domain.Team, error) {
+			team := &domain.Team{Name: teamName}
+			for _, member := range members {
+				if member.TeamName == teamName {
+					team.Members = append(team.Members, member)
+				}
+			}
+			return team, nil
+		})
+
+	prStore.
+		On("AddAssignmentDecision", ctx, mock.AnythingOfType("domain.AssignmentDecision")).
+		Return(nil).Once()
+}
@@ -0,0 +1,140 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"avito/internal/domain"
+)
+
+const maxTagLength = 32
+
+// SetUserTags replaces tags of the member of the team.
+func (s *Service) SetUserTags(ctx context.Context, teamName, userID string, tags []string) (*domain.User, error) {
+	tags, err := normalizeTags(tags)
+	if err != nil {
+		return nil, err
+	}
+
+	user, err := s.userStore.GetUserByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if user.TeamName != teamName {
+		return nil, fmt.Errorf("%w: user %q is not a member of team %q", domain.ErrNotFound, userID, teamName)
+	}
+
+	if err := s.userStore.SetTags(ctx, userID, tags); err != nil {
+		return nil, err
+	}
+
+	user.Tags = tags
+	return user, nil
+}
+
+// normalizeTags lower cases tags and drops duplicates keeping the order.
+func normalizeTags(tags []string) ([]string, error) {
+	out := make([]string, 0, len(tags))
+	for _, tag := range tags {
+		tag = strings.ToLower(strings.TrimSpace(tag))
+		if tag == "" || len(tag) > maxTagLength || strings.ContainsAny(tag, " \t\n,") {
+			return nil, fmt.Errorf("%w: bad tag %q", domain.ErrInvalidInput, tag)
+		}
+		out = appendMissing(out, tag)
+	}
+	return out, nil
+}
+
+// pickTagged chooses reviewers of the team of settings covering tags greedily:
+// every step a candidate covering the most of still uncovered tags is taken,
+// equally good candidates are left to the selector.
+func (s *Service) pickTagged(ctx context.Context, pool *candidatePool, settings domain.TeamSettings, authorID string, exclude, tags []string, quantity int) ([]string, error) {
+	if len(tags) == 0 || quantity <= 0 {
+		return nil, nil
+	}
+
+	candidates, _, err := s.availableCandidates(ctx, pool, settings.TeamName, exclude)
+	if err != nil {
+		return nil, err
+	}
+
+	prefer := preferenceOf(settings, time.Now())
+	uncovered := slices.Clone(tags)
+	chosen := make([]string, 0)
+	for len(uncovered) > 0 && len(chosen) < quantity {
+		best, bestUsers := 0, make([]domain.User, 0)
+		for _, user := range candidates {
+			if slices.Contains(chosen, user.ID) {
+				continue
+			}
+			covers := len(intersectTags(user.Tags, uncovered))
+			switch {
+			case covers > best:
+				best, bestUsers = covers, []domain.User{user}
+			case covers == best && covers > 0:
+				bestUsers = append(bestUsers, user)
+			}
+		}
+		if best == 0 {
+			break
+		}
+
+		picked, err := s.selectPreferred(ctx, pool, SelectInput{
+			TeamName:   settings.TeamName,
+			Strategy:   settings.Strategy,
+			AuthorID:   authorID,
+			Candidates: bestUsers,
+			Quantity:   1,
+			Counter:    pool,
+		}, prefer)
+		if err != nil {
+			return nil, err
+		}
+		if len(picked) == 0 {
+			break
+		}
+
+		chosen = append(chosen, picked[0])
+		for _, user := range bestUsers {
+			if user.ID == picked[0] {
+				uncovered = slices.DeleteFunc(uncovered, func(tag string) bool {
+					return slices.Contains(user.Tags, tag)
+				})
+			}
+		}
+	}
+	return chosen, nil
+}
+
+// matchedTags returns required tags of every reviewer known to the pool,
+// reviewers without any of them are omitted.
+func matchedTags(pool *candidatePool, reviewers, tags []string) map[string][]string {
+	out := make(map[string][]string)
+	if len(tags) == 0 {
+		return out
+	}
+	for _, id := range reviewers {
+		user, ok := pool.cachedUser(id)
+		if !ok {
+			continue
+		}
+		if matched := intersectTags(user.Tags, tags); len(matched) > 0 {
+			out[id] = matched
+		}
+	}
+	return out
+}
+
+// intersectTags returns tags of wanted which user tags contain, in order of wanted.
+func intersectTags(userTags, wanted []string) []string {
+	out := make([]string, 0)
+	for _, tag := range wanted {
+		if slices.Contains(userTags, tag) {
+			out = append(out, tag)
+		}
+	}
+	return out
+}
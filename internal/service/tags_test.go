@@ -0,0 +1,115 @@
+package service
+
+import (
+	"avito/internal/domain"
+	"avito/internal/service/mocks"
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNormalizeTags(t *testing.T) {
+	got, err := normalizeTags([]string{" Go", "sql", "go", "FRONTEND"})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"go", "sql", "frontend"}, got)
+
+	_, err = normalizeTags([]string{"go", " "})
+	require.ErrorIs(t, err, domain.ErrInvalidInput)
+
+	_, err = normalizeTags([]string{"go,sql"})
+	require.ErrorIs(t, err, domain.ErrInvalidInput)
+}
+
+func TestService_CreatePullRequest_PrefersTaggedReviewers(t *testing.T) {
+	ctx := context.Background()
+
+	tests := []struct {
+		name          string
+		requiredTags  []string
+		wantReviewers []string
+		wantMatched   map[string][]string
+		wantCovered   []string
+	}{
+		{
+			name:          "one_covers_all",
+			requiredTags:  []string{"go", "sql"},
+			wantReviewers: []string{"u4"},
+			wantMatched:   map[string][]string{"u4": {"go", "sql"}},
+			wantCovered:   []string{"go", "sql"},
+		},
+		{
+			name:          "covered_by_two",
+			requiredTags:  []string{"frontend", "SQL", "python"},
+			wantReviewers: []string{"u2"},
+			wantMatched:   map[string][]string{"u2": {"frontend"}},
+			wantCovered:   []string{"frontend", "sql"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			prStore := mocks.NewPullRequestStorage(t)
+			userStore := mocks.NewUserStorage(t)
+			teamStore := mocks.NewTeamStorage(t)
+			tx := &mockTxManager{}
+
+			author := &domain.User{ID: "u1", TeamName: "team-A", IsActive: true}
+
+			prStore.
+				On("GetPullRequestByID", ctx, "pr-1").
+				Return(domain.PullRequest{}, domain.ErrNotFound).Once()
+
+			userStore.
+				On("GetUserByID", ctx, "u1").
+				Return(author, nil).Once()
+
+			teamStore.
+				On("GetTeamSettings", ctx, "team-A").
+				Return(defaultSettings, nil).Once()
+
+			userStore.
+				On("ListActiveUserByTeam", ctx, "team-A").
+				Return([]domain.User{
+					*author,
+					{ID: "u2", TeamName: "team-A", IsActive: true, Tags: []string{"go", "frontend"}},
+					{ID: "u3", TeamName: "team-A", IsActive: true, Tags: []string{"sql"}},
+					{ID: "u4", TeamName: "team-A", IsActive: true, Tags: []string{"go", "sql"}},
+					{ID: "u5", TeamName: "team-A", IsActive: true},
+				}, nil).Once()
+
+			prStore.
+				On("Create", ctx, mock.AnythingOfType("domain.PullRequest")).
+				Return(nil).Once()
+
+			prStore.
+				On("AddAssignmentAudits", ctx, mock.Anything).
+				Return(nil).Once()
+
+			expectDecision(ctx, teamStore, prStore)
+
+			svc := NewService(teamStore, userStore, prStore, tx, NewRandomSelector(), nil)
+
+			got, err := svc.CreatePullRequest(ctx, domain.PullRequestCreate{
+				ID:           "pr-1",
+				Name:         "PR name",
+				AuthorID:     "u1",
+				RequiredTags: tt.requiredTags,
+			})
+			require.NoError(t, err)
+			require.Len(t, got.AssignedReviewers, 2)
+			assert.Subset(t, got.AssignedReviewers, tt.wantReviewers)
+			for id, tags := range tt.wantMatched {
+				assert.Equal(t, tags, got.MatchedTags[id])
+			}
+
+			covered := make([]string, 0)
+			for _, tags := range got.MatchedTags {
+				covered = append(covered, tags...)
+			}
+			assert.Subset(t, covered, tt.wantCovered)
+		})
+	}
+}
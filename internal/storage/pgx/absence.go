@@ -0,0 +1,109 @@
+package pgx
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"avito/internal/domain"
+
+	"github.com/jackc/pgx/v5"
+)
+
+func (s *Storage) AddAbsence(ctx context.Context, absence domain.Absence) (int64, error) {
+	const query = `
+		INSERT INTO user_absences (user_id, start_date, end_date, reason, created_at)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING id;
+	`
+
+	var id int64
+	err := s.getExecutor(ctx).QueryRow(ctx, query,
+		absence.UserID,
+		absence.StartDate,
+		absence.EndDate,
+		absence.Reason,
+		absence.CreatedAt,
+	).Scan(&id)
+	if err != nil {
+		return 0, err
+	}
+
+	return id, nil
+}
+
+func (s *Storage) GetAbsenceByID(ctx context.Context, absenceID int64) (domain.Absence, error) {
+	const query = `
+		SELECT id, user_id, start_date, end_date, reason, created_at, cancelled_at
+		  FROM user_absences
+		 WHERE id = $1;
+	`
+
+	var absence domain.Absence
+	err := s.getExecutor(ctx).QueryRow(ctx, query, absenceID).Scan(
+		&absence.ID,
+		&absence.UserID,
+		&absence.StartDate,
+		&absence.EndDate,
+		&absence.Reason,
+		&absence.CreatedAt,
+		&absence.CancelledAt,
+	)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return domain.Absence{}, domain.ErrNotFound
+		}
+		return domain.Absence{}, err
+	}
+
+	return absence, nil
+}
+
+func (s *Storage) ListAbsences(ctx context.Context, userID string) ([]domain.Absence, error) {
+	const query = `
+		SELECT id, user_id, start_date, end_date, reason, created_at, cancelled_at
+		  FROM user_absences
+		 WHERE user_id = $1
+		 ORDER BY start_date, id;
+	`
+
+	rows, err := s.getExecutor(ctx).Query(ctx, query, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	out := make([]domain.Absence, 0)
+	for rows.Next() {
+		var absence domain.Absence
+		if err := rows.Scan(
+			&absence.ID,
+			&absence.UserID,
+			&absence.StartDate,
+			&absence.EndDate,
+			&absence.Reason,
+			&absence.CreatedAt,
+			&absence.CancelledAt,
+		); err != nil {
+			return nil, err
+		}
+		out = append(out, absence)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return out, nil
+}
+
+func (s *Storage) CancelAbsence(ctx context.Context, absenceID int64, cancelledAt time.Time) error {
+	const query = `
+		UPDATE user_absences
+		   SET cancelled_at = $2
+		 WHERE id = $1
+		   AND cancelled_at IS NULL;
+	`
+
+	_, err := s.getExecutor(ctx).Exec(ctx, query, absenceID, cancelledAt)
+	return err
+}
@@ -0,0 +1,87 @@
+package pgx
+
+import (
+	"context"
+	"encoding/json"
+	"time"
+
+	"avito/internal/domain"
+)
+
+// AddAssignmentAudits writes all audits with one statement.
+func (s *Storage) AddAssignmentAudits(ctx context.Context, audits []domain.AssignmentAudit) error {
+	const query = `
+		INSERT INTO assignment_audit (pull_request_id, action, seed, steps, created_at)
+		SELECT * FROM unnest($1::text[], $2::text[], $3::bigint[], $4::jsonb[], $5::timestamptz[]);
+	`
+
+	if len(audits) == 0 {
+		return nil
+	}
+
+	var (
+		prIDs     = make([]string, 0, len(audits))
+		actions   = make([]string, 0, len(audits))
+		seeds     = make([]int64, 0, len(audits))
+		steps     = make([]string, 0, len(audits))
+		createdAt = make([]time.Time, 0, len(audits))
+	)
+	for _, audit := range audits {
+		raw, err := json.Marshal(selectionStepsToDAO(audit.Steps))
+		if err != nil {
+			return err
+		}
+		prIDs = append(prIDs, audit.PullRequestID)
+		actions = append(actions, string(audit.Action))
+		seeds = append(seeds, int64(audit.Seed)) // stored bit for bit
+		steps = append(steps, string(raw))
+		createdAt = append(createdAt, audit.CreatedAt)
+	}
+
+	_, err := s.getExecutor(ctx).Exec(ctx, query, prIDs, actions, seeds, steps, createdAt)
+	return err
+}
+
+// ListAssignmentAudits returns audits of the pull request, oldest first.
+func (s *Storage) ListAssignmentAudits(ctx context.Context, pullRequestID string) ([]domain.AssignmentAudit, error) {
+	const query = `
+		SELECT id, pull_request_id, action, seed, steps, created_at
+		  FROM assignment_audit
+		 WHERE pull_request_id = $1
+		 ORDER BY id;
+	`
+
+	rows, err := s.getExecutor(ctx).Query(ctx, query, pullRequestID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	out := make([]domain.AssignmentAudit, 0)
+	for rows.Next() {
+		var (
+			audit  domain.AssignmentAudit
+			action string
+			seed   int64
+			raw    []byte
+		)
+		if err := rows.Scan(&audit.ID, &audit.PullRequestID, &action, &seed, &raw, &audit.CreatedAt); err != nil {
+			return nil, err
+		}
+
+		var steps []selectionStepDAO
+		if err := json.Unmarshal(raw, &steps); err != nil {
+			return nil, err
+		}
+
+		audit.Action = domain.AssignmentAction(action)
+		audit.Seed = uint64(seed)
+		audit.Steps = selectionStepsFromDAO(steps)
+		out = append(out, audit)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return out, nil
+}
@@ -15,6 +15,22 @@ type pullRequestDAO struct {
 	CreatedAt time.Time
 	MergedAt  sql.NullTime
 	Reviewers []string
+	Fallback  []string
+	Mandatory []string
+
+	OverrideBy     sql.NullString
+	OverrideReason sql.NullString
+
+	ClosedAt   sql.NullTime
+	ClosedFrom sql.NullString
+
+	Description string
+	URL         string
+	Labels      []string
+
+	// States and StateUpdatedAt are aligned with Reviewers.
+	States         []string
+	StateUpdatedAt []*time.Time
 }
 
 func pullRequestDAOToDomain(pr pullRequestDAO) domain.PullRequest {
@@ -26,13 +42,172 @@ func pullRequestDAOToDomain(pr pullRequestDAO) domain.PullRequest {
 		mergedAt = nil
 	}
 
+	reviews := make(map[string]domain.Review, len(pr.States))
+	for i, state := range pr.States {
+		if i >= len(pr.Reviewers) || i >= len(pr.StateUpdatedAt) {
+			break
+		}
+		reviews[pr.Reviewers[i]] = domain.Review{
+			State:     domain.ReviewState(state),
+			UpdatedAt: pr.StateUpdatedAt[i],
+		}
+	}
+
+	var closedAt *time.Time
+	if pr.ClosedAt.Valid {
+		t := pr.ClosedAt.Time
+		closedAt = &t
+	}
+
+	var override *domain.MergeOverride
+	if pr.OverrideBy.Valid {
+		override = &domain.MergeOverride{
+			By:     pr.OverrideBy.String,
+			Reason: pr.OverrideReason.String,
+		}
+	}
+
 	return domain.PullRequest{
-		ID:                pr.ID,
-		Name:              pr.Name,
-		AuthorID:          pr.AuthorID,
-		Status:            domain.PullRequestStatus(pr.Status),
-		CreatedAt:         &pr.CreatedAt,
-		MergedAt:          mergedAt,
-		AssignedReviewers: pr.Reviewers,
+		ID:                 pr.ID,
+		Name:               pr.Name,
+		Description:        pr.Description,
+		URL:                pr.URL,
+		Labels:             pr.Labels,
+		AuthorID:           pr.AuthorID,
+		Status:             domain.PullRequestStatus(pr.Status),
+		CreatedAt:          &pr.CreatedAt,
+		MergedAt:           mergedAt,
+		AssignedReviewers:  pr.Reviewers,
+		FallbackReviewers:  pr.Fallback,
+		MandatoryReviewers: pr.Mandatory,
+		Reviews:            reviews,
+		MergeOverride:      override,
+		ClosedAt:           closedAt,
+		ClosedFrom:         domain.PullRequestStatus(pr.ClosedFrom.String),
+	}
+}
+
+// selectionStepDAO json of one element of assignment_audit.steps.
+type selectionStepDAO struct {
+	TeamName   string              `json:"team_name"`
+	Strategy   string              `json:"strategy"`
+	Candidates []string            `json:"candidates"`
+	Quantity   int                 `json:"quantity"`
+	Chosen     []string            `json:"chosen"`
+	Inputs     *selectionInputsDAO `json:"inputs,omitempty"`
+}
+
+type selectionInputsDAO struct {
+	Loads   map[string]int `json:"loads,omitempty"`
+	Pairs   map[string]int `json:"pairs,omitempty"`
+	Weights map[string]int `json:"weights,omitempty"`
+	Last    string         `json:"last,omitempty"`
+}
+
+func selectionStepsToDAO(steps []domain.SelectionStep) []selectionStepDAO {
+	out := make([]selectionStepDAO, 0, len(steps))
+	for _, step := range steps {
+		out = append(out, selectionStepDAO{
+			TeamName:   step.TeamName,
+			Strategy:   string(step.Strategy),
+			Candidates: step.Candidates,
+			Quantity:   step.Quantity,
+			Chosen:     step.Chosen,
+			Inputs:     (*selectionInputsDAO)(step.Inputs),
+		})
+	}
+	return out
+}
+
+func selectionStepsFromDAO(steps []selectionStepDAO) []domain.SelectionStep {
+	out := make([]domain.SelectionStep, 0, len(steps))
+	for _, step := range steps {
+		out = append(out, domain.SelectionStep{
+			TeamName:   step.TeamName,
+			Strategy:   domain.SelectionStrategy(step.Strategy),
+			Candidates: step.Candidates,
+			Quantity:   step.Quantity,
+			Chosen:     step.Chosen,
+			Inputs:     (*domain.SelectionInputs)(step.Inputs),
+		})
+	}
+	return out
+}
+
+// teamCandidatesDAO json of one element of assignment_decisions.teams.
+type teamCandidatesDAO struct {
+	TeamName string         `json:"team_name"`
+	Eligible []string       `json:"eligible"`
+	Excluded []exclusionDAO `json:"excluded"`
+}
+
+type exclusionDAO struct {
+	UserID string `json:"user_id"`
+	Reason string `json:"reason"`
+}
+
+// chosenReviewerDAO json of one element of assignment_decisions.reviewers.
+type chosenReviewerDAO struct {
+	UserID     string `json:"user_id"`
+	TeamName   string `json:"team_name"`
+	Reason     string `json:"reason"`
+	IsFallback bool   `json:"is_fallback"`
+}
+
+func teamCandidatesToDAO(teams []domain.TeamCandidates) []teamCandidatesDAO {
+	out := make([]teamCandidatesDAO, 0, len(teams))
+	for _, team := range teams {
+		excluded := make([]exclusionDAO, 0, len(team.Excluded))
+		for _, exclusion := range team.Excluded {
+			excluded = append(excluded, exclusionDAO{UserID: exclusion.UserID, Reason: string(exclusion.Reason)})
+		}
+		out = append(out, teamCandidatesDAO{
+			TeamName: team.TeamName,
+			Eligible: team.Eligible,
+			Excluded: excluded,
+		})
+	}
+	return out
+}
+
+func teamCandidatesFromDAO(teams []teamCandidatesDAO) []domain.TeamCandidates {
+	out := make([]domain.TeamCandidates, 0, len(teams))
+	for _, team := range teams {
+		excluded := make([]domain.Exclusion, 0, len(team.Excluded))
+		for _, exclusion := range team.Excluded {
+			excluded = append(excluded, domain.Exclusion{UserID: exclusion.UserID, Reason: domain.ExclusionReason(exclusion.Reason)})
+		}
+		out = append(out, domain.TeamCandidates{
+			TeamName: team.TeamName,
+			Eligible: team.Eligible,
+			Excluded: excluded,
+		})
+	}
+	return out
+}
+
+func chosenReviewersToDAO(reviewers []domain.ChosenReviewer) []chosenReviewerDAO {
+	out := make([]chosenReviewerDAO, 0, len(reviewers))
+	for _, reviewer := range reviewers {
+		out = append(out, chosenReviewerDAO{
+			UserID:     reviewer.UserID,
+			TeamName:   reviewer.TeamName,
+			Reason:     string(reviewer.Reason),
+			IsFallback: reviewer.IsFallback,
+		})
+	}
+	return out
+}
+
+func chosenReviewersFromDAO(reviewers []chosenReviewerDAO) []domain.ChosenReviewer {
+	out := make([]domain.ChosenReviewer, 0, len(reviewers))
+	for _, reviewer := range reviewers {
+		out = append(out, domain.ChosenReviewer{
+			UserID:     reviewer.UserID,
+			TeamName:   reviewer.TeamName,
+			Reason:     domain.ChoiceReason(reviewer.Reason),
+			IsFallback: reviewer.IsFallback,
+		})
 	}
+	return out
 }
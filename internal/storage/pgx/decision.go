@@ -0,0 +1,82 @@
+package pgx
+
+import (
+	"context"
+	"encoding/json"
+
+	"avito/internal/domain"
+)
+
+func (s *Storage) AddAssignmentDecision(ctx context.Context, decision domain.AssignmentDecision) error {
+	const query = `
+		INSERT INTO assignment_decisions (pull_request_id, action, strategy, teams, reviewers, created_at)
+		VALUES ($1, $2, NULLIF($3, ''), $4, $5, $6);
+	`
+
+	teams, err := json.Marshal(teamCandidatesToDAO(decision.Teams))
+	if err != nil {
+		return err
+	}
+	reviewers, err := json.Marshal(chosenReviewersToDAO(decision.Reviewers))
+	if err != nil {
+		return err
+	}
+
+	_, err = s.getExecutor(ctx).Exec(ctx, query,
+		decision.PullRequestID,
+		string(decision.Action),
+		string(decision.Strategy),
+		teams,
+		reviewers,
+		decision.CreatedAt,
+	)
+	return err
+}
+
+// ListAssignmentDecisions returns decisions of the pull request, oldest first.
+func (s *Storage) ListAssignmentDecisions(ctx context.Context, pullRequestID string) ([]domain.AssignmentDecision, error) {
+	const query = `
+		SELECT id, pull_request_id, action, COALESCE(strategy, ''), teams, reviewers, created_at
+		  FROM assignment_decisions
+		 WHERE pull_request_id = $1
+		 ORDER BY id;
+	`
+
+	rows, err := s.getExecutor(ctx).Query(ctx, query, pullRequestID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	out := make([]domain.AssignmentDecision, 0)
+	for rows.Next() {
+		var (
+			decision               domain.AssignmentDecision
+			action, strategy       string
+			rawTeams, rawReviewers []byte
+		)
+		if err := rows.Scan(&decision.ID, &decision.PullRequestID, &action, &strategy, &rawTeams, &rawReviewers, &decision.CreatedAt); err != nil {
+			return nil, err
+		}
+
+		var teams []teamCandidatesDAO
+		if err := json.Unmarshal(rawTeams, &teams); err != nil {
+			return nil, err
+		}
+		var reviewers []chosenReviewerDAO
+		if err := json.Unmarshal(rawReviewers, &reviewers); err != nil {
+			return nil, err
+		}
+
+		decision.Action = domain.AssignmentAction(action)
+		decision.Strategy = domain.SelectionStrategy(strategy)
+		decision.Teams = teamCandidatesFromDAO(teams)
+		decision.Reviewers = chosenReviewersFromDAO(reviewers)
+		out = append(out, decision)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return out, nil
+}
@@ -3,6 +3,10 @@ package pgx
 import (
 	"context"
 	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"strings"
 	"time"
 
 	"avito/internal/domain"
@@ -40,13 +44,27 @@ func (s *Storage) Create(ctx context.Context, pr domain.PullRequest) error {
 		return err
 	}
 
-	const queryInsertReviewers = `
-		INSERT INTO pull_request_reviewers (pull_request_id, user_id)
-		VALUES ($1, $2);
+	return s.insertReviewers(ctx, pr)
+}
+
+// MarkReady stores the status and the reviewers chosen for PR leaving DRAFT.
+func (s *Storage) MarkReady(ctx context.Context, pr domain.PullRequest) error {
+	if err := s.UpdateStatus(ctx, pr.ID, pr.Status); err != nil {
+		return err
+	}
+	return s.insertReviewers(ctx, pr)
+}
+
+func (s *Storage) insertReviewers(ctx context.Context, pr domain.PullRequest) error {
+	const query = `
+		INSERT INTO pull_request_reviewers (pull_request_id, user_id, is_fallback, is_mandatory)
+		VALUES ($1, $2, $3, $4);
 	`
 
 	for _, reviewerID := range pr.AssignedReviewers {
-		if _, err := s.getExecutor(ctx).Exec(ctx, queryInsertReviewers, pr.ID, reviewerID); err != nil {
+		isFallback := slices.Contains(pr.FallbackReviewers, reviewerID)
+		isMandatory := slices.Contains(pr.MandatoryReviewers, reviewerID)
+		if _, err := s.getExecutor(ctx).Exec(ctx, query, pr.ID, reviewerID, isFallback, isMandatory); err != nil {
 			return err
 		}
 	}
@@ -54,8 +72,10 @@ func (s *Storage) Create(ctx context.Context, pr domain.PullRequest) error {
 	return nil
 }
 
-func (s *Storage) GetPullRequestByID(ctx context.Context, pullRequestID string) (domain.PullRequest, error) {
-	const query = `
+// pullRequestSelect selects pull requests as p with their labels and reviewers in the order
+// scanPullRequest expects. Reviewers and their states are aligned by user_id, callers add
+// joins, conditions and ordering.
+const pullRequestSelect = `
 		SELECT
 		    p.id,
 		    p.name,
@@ -63,110 +83,185 @@ func (s *Storage) GetPullRequestByID(ctx context.Context, pullRequestID string)
 		    p.status,
 		    p.created_at,
 		    p.merged_at,
-		    COALESCE(
-		        array_agg(r.user_id) FILTER (WHERE r.user_id IS NOT NULL),
-		        '{}'
-		    ) AS reviewers
+		    p.merge_override_by,
+		    p.merge_override_reason,
+		    p.closed_at,
+		    p.closed_from,
+		    p.description,
+		    p.url,
+		    ARRAY(
+		        SELECT l.label
+		          FROM pull_request_labels l
+		         WHERE l.pull_request_id = p.id
+		         ORDER BY l.label
+		    ) AS labels,
+		    rv.reviewers,
+		    rv.fallback_reviewers,
+		    rv.mandatory_reviewers,
+		    rv.review_states,
+		    rv.review_state_updated_at
 		  FROM pull_requests p
-		  LEFT JOIN pull_request_reviewers r
-		         ON r.pull_request_id = p.id
-		 WHERE p.id = $1
-		 GROUP BY p.id, p.name, p.author_id, p.status, p.created_at, p.merged_at;
-	`
-
-	var prDao pullRequestDAO
-	err := s.getExecutor(ctx).QueryRow(ctx, query, pullRequestID).Scan(
-		&prDao.ID,
-		&prDao.Name,
-		&prDao.AuthorID,
-		&prDao.Status,
-		&prDao.CreatedAt,
-		&prDao.MergedAt,
-		&prDao.Reviewers)
-	if err != nil {
-		if errors.Is(err, pgx.ErrNoRows) {
-			return domain.PullRequest{}, domain.ErrNotFound
-		}
-		return domain.PullRequest{}, err
-	}
+		 CROSS JOIN LATERAL (
+		        SELECT
+		            COALESCE(array_agg(r.user_id ORDER BY r.user_id), '{}') AS reviewers,
+		            COALESCE(array_agg(r.user_id ORDER BY r.user_id) FILTER (WHERE r.is_fallback), '{}') AS fallback_reviewers,
+		            COALESCE(array_agg(r.user_id ORDER BY r.user_id) FILTER (WHERE r.is_mandatory), '{}') AS mandatory_reviewers,
+		            COALESCE(array_agg(r.state ORDER BY r.user_id), '{}') AS review_states,
+		            COALESCE(array_agg(r.state_updated_at ORDER BY r.user_id), '{}') AS review_state_updated_at
+		          FROM pull_request_reviewers r
+		         WHERE r.pull_request_id = p.id
+		       ) rv`
+
+func (s *Storage) GetPullRequestByID(ctx context.Context, pullRequestID string) (domain.PullRequest, error) {
+	const query = pullRequestSelect + `
+		 WHERE p.id = $1;
+	`
 
-	return pullRequestDAOToDomain(prDao), nil
+	pr, err := scanPullRequest(s.getExecutor(ctx).QueryRow(ctx, query, pullRequestID))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return domain.PullRequest{}, domain.ErrNotFound
+	}
+	return pr, err
 }
 
 func (s *Storage) GetPullRequestByIDForUpdate(ctx context.Context, pullRequestID string) (domain.PullRequest, error) {
-	const queryPR = `
-		SELECT id, name, author_id, status, created_at, merged_at
-		  FROM pull_requests
-		 WHERE id = $1
-		 FOR UPDATE;
-	`
-
-	var prDao pullRequestDAO
-	err := s.getExecutor(ctx).QueryRow(ctx, queryPR, pullRequestID).Scan(
-		&prDao.ID,
-		&prDao.Name,
-		&prDao.AuthorID,
-		&prDao.Status,
-		&prDao.CreatedAt,
-		&prDao.MergedAt,
-	)
-	if err != nil {
-		if errors.Is(err, pgx.ErrNoRows) {
-			return domain.PullRequest{}, domain.ErrNotFound
-		}
-		return domain.PullRequest{}, err
+	const query = pullRequestSelect + `
+		 WHERE p.id = $1
+		   FOR UPDATE OF p;
+	`
+
+	pr, err := scanPullRequest(s.getExecutor(ctx).QueryRow(ctx, query, pullRequestID))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return domain.PullRequest{}, domain.ErrNotFound
 	}
+	return pr, err
+}
 
-	const queryReviewers = `
-		SELECT user_id
-		  FROM pull_request_reviewers
-		 WHERE pull_request_id = $1;
+// UpdateStatusMerged override is nil when the merge rule of the team was satisfied.
+func (s *Storage) UpdateStatusMerged(ctx context.Context, pullRequestID string, mergedAt *time.Time, override *domain.MergeOverride) error {
+	const query = `
+		UPDATE pull_requests
+		   SET status                = $2,
+		       merged_at             = $3,
+		       merge_override_by     = $4,
+		       merge_override_reason = $5
+		 WHERE id = $1;
+	`
+
+	if mergedAt == nil {
+		return errors.New("mergedAt is nil in UpdateStatusMerged")
+	}
+
+	var overrideBy, overrideReason any
+	if override != nil {
+		overrideBy = override.By
+		overrideReason = override.Reason
+	}
+
+	_, err := s.getExecutor(ctx).Exec(ctx, query,
+		pullRequestID,
+		string(domain.PRStatusMerged),
+		*mergedAt,
+		overrideBy,
+		overrideReason,
+	)
+	return err
+}
+
+// UpdateMetadata stores name, description and url of PR.
+func (s *Storage) UpdateMetadata(ctx context.Context, pr domain.PullRequest) error {
+	const query = `
+		UPDATE pull_requests
+		   SET name        = $2,
+		       description = $3,
+		       url         = $4
+		 WHERE id = $1;
 	`
 
-	rows, err := s.getExecutor(ctx).Query(ctx, queryReviewers, pullRequestID)
+	cmd, err := s.getExecutor(ctx).Exec(ctx, query, pr.ID, pr.Name, pr.Description, pr.URL)
 	if err != nil {
-		return domain.PullRequest{}, err
+		return err
 	}
-	defer rows.Close()
+	if cmd.RowsAffected() == 0 {
+		return domain.ErrNotFound
+	}
+	return nil
+}
 
-	reviewers := make([]string, 0)
-	for rows.Next() {
-		var id string
-		if err := rows.Scan(&id); err != nil {
-			return domain.PullRequest{}, err
-		}
-		reviewers = append(reviewers, id)
+// SetLabels replaces all labels of PR.
+func (s *Storage) SetLabels(ctx context.Context, pullRequestID string, labels []string) error {
+	const deleteQuery = `
+		DELETE FROM pull_request_labels
+		 WHERE pull_request_id = $1;
+	`
+
+	if _, err := s.getExecutor(ctx).Exec(ctx, deleteQuery, pullRequestID); err != nil {
+		return err
 	}
-	if err := rows.Err(); err != nil {
-		return domain.PullRequest{}, err
+	if len(labels) == 0 {
+		return nil
 	}
 
-	prDao.Reviewers = reviewers
+	const insertQuery = `
+		INSERT INTO pull_request_labels (pull_request_id, label)
+		SELECT $1, unnest($2::text[]);
+	`
+
+	_, err := s.getExecutor(ctx).Exec(ctx, insertQuery, pullRequestID, labels)
+	return err
+}
+
+// UpdateStatusClosed remembers the status PR is closed from to restore it on reopen.
+func (s *Storage) UpdateStatusClosed(ctx context.Context, pullRequestID string, closedAt time.Time) error {
+	const query = `
+		UPDATE pull_requests
+		   SET closed_from = status,
+		       status      = $2,
+		       closed_at   = $3
+		 WHERE id = $1;
+	`
 
-	return pullRequestDAOToDomain(prDao), nil
+	cmd, err := s.getExecutor(ctx).Exec(ctx, query, pullRequestID, string(domain.PRStatusClosed), closedAt)
+	if err != nil {
+		return err
+	}
+	if cmd.RowsAffected() == 0 {
+		return domain.ErrNotFound
+	}
+	return nil
 }
 
-func (s *Storage) UpdateStatusMerged(ctx context.Context, pullRequestID string, mergedAt *time.Time) error {
+// UpdateStatus moves PR between not finished statuses, marks of closing are cleared.
+func (s *Storage) UpdateStatus(ctx context.Context, pullRequestID string, status domain.PullRequestStatus) error {
 	const query = `
 		UPDATE pull_requests
-		   SET status   = $2,
-		       merged_at = $3
+		   SET status      = $2,
+		       closed_at   = NULL,
+		       closed_from = NULL
 		 WHERE id = $1;
 	`
 
-	if mergedAt == nil {
-		return errors.New("mergedAt is nil in UpdateStatusMerged")
+	cmd, err := s.getExecutor(ctx).Exec(ctx, query, pullRequestID, string(status))
+	if err != nil {
+		return err
+	}
+	if cmd.RowsAffected() == 0 {
+		return domain.ErrNotFound
 	}
+	return nil
+}
 
-	_, err := s.getExecutor(ctx).Exec(ctx, query,
-		pullRequestID,
-		string(domain.PRStatusMerged),
-		*mergedAt,
-	)
+func (s *Storage) AddReviewer(ctx context.Context, pullRequestID string, userID string, isFallback, isMandatory bool) error {
+	const query = `
+		INSERT INTO pull_request_reviewers (pull_request_id, user_id, is_fallback, is_mandatory)
+		VALUES ($1, $2, $3, $4);
+	`
+
+	_, err := s.getExecutor(ctx).Exec(ctx, query, pullRequestID, userID, isFallback, isMandatory)
 	return err
 }
 
-func (s *Storage) ReplaceReviewer(ctx context.Context, pullRequestID string, oldID string, newID string) error {
+func (s *Storage) ReplaceReviewer(ctx context.Context, pullRequestID string, oldID string, newID string, isFallback bool) error {
 	const deleteQuery = `
 		DELETE FROM pull_request_reviewers
 		 WHERE pull_request_id = $1
@@ -182,61 +277,400 @@ func (s *Storage) ReplaceReviewer(ctx context.Context, pullRequestID string, old
 	}
 
 	const insertQuery = `
-		INSERT INTO pull_request_reviewers (pull_request_id, user_id)
-		VALUES ($1, $2);
+		INSERT INTO pull_request_reviewers (pull_request_id, user_id, is_fallback)
+		VALUES ($1, $2, $3);
 	`
 
-	_, err = s.getExecutor(ctx).Exec(ctx, insertQuery, pullRequestID, newID)
+	_, err = s.getExecutor(ctx).Exec(ctx, insertQuery, pullRequestID, newID, isFallback)
 	return err
 }
 
-func (s *Storage) ListByReviewer(ctx context.Context, userID string) ([]domain.PullRequest, error) {
+func (s *Storage) RemoveReviewer(ctx context.Context, pullRequestID string, userID string) error {
 	const query = `
-		SELECT
-		    p.id,
-		    p.name,
-		    p.author_id,
-		    p.status,
-		    p.created_at,
-		    p.merged_at,
-		    COALESCE(
-		        array_agg(r2.user_id) FILTER (WHERE r2.user_id IS NOT NULL),
-		        '{}'
-		    ) AS reviewers
-		  FROM pull_requests p
-		  JOIN pull_request_reviewers r
-		    ON r.pull_request_id = p.id
-		  LEFT JOIN pull_request_reviewers r2
-		    ON r2.pull_request_id = p.id
-		 WHERE r.user_id = $1
-		 GROUP BY p.id, p.name, p.author_id, p.status, p.created_at, p.merged_at;
+		DELETE FROM pull_request_reviewers
+		 WHERE pull_request_id = $1
+		   AND user_id         = $2;
+	`
+
+	cmd, err := s.getExecutor(ctx).Exec(ctx, query, pullRequestID, userID)
+	if err != nil {
+		return err
+	}
+	if cmd.RowsAffected() == 0 {
+		return domain.ErrNotAssigned
+	}
+	return nil
+}
+
+// SetReviewState stores the verdict of the assigned reviewer.
+func (s *Storage) SetReviewState(ctx context.Context, pullRequestID string, userID string, state domain.ReviewState, at time.Time) error {
+	const query = `
+		UPDATE pull_request_reviewers
+		   SET state            = $3,
+		       state_updated_at = $4
+		 WHERE pull_request_id = $1
+		   AND user_id         = $2;
+	`
+
+	cmd, err := s.getExecutor(ctx).Exec(ctx, query, pullRequestID, userID, string(state), at)
+	if err != nil {
+		return err
+	}
+	if cmd.RowsAffected() == 0 {
+		return domain.ErrNotAssigned
+	}
+	return nil
+}
+
+// ReplaceReviewers applies all reassignments with one statement.
+func (s *Storage) ReplaceReviewers(ctx context.Context, reassignments []domain.Reassignment) error {
+	const query = `
+		UPDATE pull_request_reviewers r
+		   SET user_id          = v.new_id,
+		       is_fallback      = v.is_fallback,
+		       is_mandatory     = false,
+		       state            = 'PENDING',
+		       state_updated_at = NULL
+		  FROM unnest($1::text[], $2::text[], $3::text[], $4::boolean[])
+		       AS v(pull_request_id, old_id, new_id, is_fallback)
+		 WHERE r.pull_request_id = v.pull_request_id
+		   AND r.user_id         = v.old_id;
 	`
 
-	exec := s.getExecutor(ctx)
+	var (
+		prIDs      = make([]string, 0, len(reassignments))
+		oldIDs     = make([]string, 0, len(reassignments))
+		newIDs     = make([]string, 0, len(reassignments))
+		isFallback = make([]bool, 0, len(reassignments))
+	)
+	for _, reassignment := range reassignments {
+		prIDs = append(prIDs, reassignment.PullRequestID)
+		oldIDs = append(oldIDs, reassignment.OldReviewerID)
+		newIDs = append(newIDs, reassignment.NewReviewerID)
+		isFallback = append(isFallback, reassignment.IsFallback)
+	}
+
+	cmd, err := s.getExecutor(ctx).Exec(ctx, query, prIDs, oldIDs, newIDs, isFallback)
+	if err != nil {
+		return err
+	}
+	if cmd.RowsAffected() != int64(len(reassignments)) {
+		return domain.ErrNotAssigned
+	}
+	return nil
+}
+
+// CountOpenReviews returns number of open (OPEN, NEEDS_REVIEWERS) pull requests per reviewer,
+// users without open reviews are absent in result.
+func (s *Storage) CountOpenReviews(ctx context.Context, userIDs []string) (map[string]int, error) {
+	const query = `
+		SELECT r.user_id, count(*)
+		  FROM pull_request_reviewers r
+		  JOIN pull_requests p
+		    ON p.id = r.pull_request_id
+		 WHERE r.user_id = ANY($1)
+		   AND p.status = ANY($2)
+		 GROUP BY r.user_id;
+	`
+
+	out := make(map[string]int, len(userIDs))
+	if len(userIDs) == 0 {
+		return out, nil
+	}
 
-	rows, err := exec.Query(ctx, query, userID)
+	rows, err := s.getExecutor(ctx).Query(ctx, query, userIDs, openStatuses())
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
 
-	out := make([]domain.PullRequest, 0)
 	for rows.Next() {
-		var dao pullRequestDAO
-
-		if err := rows.Scan(
-			&dao.ID,
-			&dao.Name,
-			&dao.AuthorID,
-			&dao.Status,
-			&dao.CreatedAt,
-			&dao.MergedAt,
-			&dao.Reviewers,
-		); err != nil {
+		var (
+			userID string
+			count  int
+		)
+		if err := rows.Scan(&userID, &count); err != nil {
+			return nil, err
+		}
+		out[userID] = count
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return out, nil
+}
+
+// RecentReviewers returns reviewers of the last limit assignments to the author's
+// pull requests, newest first; a reviewer appears once per pull request.
+func (s *Storage) RecentReviewers(ctx context.Context, authorID string, limit int) ([]string, error) {
+	const query = `
+		SELECT r.user_id
+		  FROM pull_request_reviewers r
+		  JOIN pull_requests p
+		    ON p.id = r.pull_request_id
+		 WHERE p.author_id = $1
+		 ORDER BY p.created_at DESC, p.id DESC
+		 LIMIT $2;
+	`
+
+	rows, err := s.getExecutor(ctx).Query(ctx, query, authorID, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	out := make([]string, 0, limit)
+	for rows.Next() {
+		var userID string
+		if err := rows.Scan(&userID); err != nil {
 			return nil, err
 		}
+		out = append(out, userID)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return out, nil
+}
+
+// RecentReviewersByAuthors is RecentReviewers for every author with one query,
+// authors without pull requests are not in the result.
+func (s *Storage) RecentReviewersByAuthors(ctx context.Context, authorIDs []string, limit int) (map[string][]string, error) {
+	const query = `
+		SELECT a.author_id, rr.user_id
+		  FROM unnest($1::text[]) AS a(author_id)
+		 CROSS JOIN LATERAL (
+		        SELECT r.user_id, p.created_at, p.id
+		          FROM pull_request_reviewers r
+		          JOIN pull_requests p
+		            ON p.id = r.pull_request_id
+		         WHERE p.author_id = a.author_id
+		         ORDER BY p.created_at DESC, p.id DESC
+		         LIMIT $2
+		   ) rr
+		 ORDER BY a.author_id, rr.created_at DESC, rr.id DESC;
+	`
+
+	rows, err := s.getExecutor(ctx).Query(ctx, query, authorIDs, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	out := make(map[string][]string, len(authorIDs))
+	for rows.Next() {
+		var authorID, userID string
+		if err := rows.Scan(&authorID, &userID); err != nil {
+			return nil, err
+		}
+		out[authorID] = append(out[authorID], userID)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return out, nil
+}
+
+// ListByStatus returns up to limit pull requests with the status, least recently
+// backfilled first, never backfilled ones oldest first.
+func (s *Storage) ListByStatus(ctx context.Context, status domain.PullRequestStatus, limit int) ([]domain.PullRequest, error) {
+	const query = pullRequestSelect + `
+		 WHERE p.status = $1
+		 ORDER BY p.last_backfill_attempt_at NULLS FIRST, p.created_at, p.id
+		 LIMIT $2;
+	`
+
+	rows, err := s.getExecutor(ctx).Query(ctx, query, string(status), limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	return scanPullRequests(rows)
+}
+
+// ListPullRequests returns up to filter.Limit pull requests matching filter
+// ordered by (created_at, id).
+func (s *Storage) ListPullRequests(ctx context.Context, filter domain.PullRequestFilter) ([]domain.PullRequest, error) {
+	const query = pullRequestSelect + `
+		 WHERE %s
+		 ORDER BY p.created_at, p.id
+		 LIMIT %s;
+	`
+
+	var (
+		args  []any
+		conds []string
+	)
+	arg := func(v any) string {
+		args = append(args, v)
+		return "$" + strconv.Itoa(len(args))
+	}
+
+	if len(filter.Statuses) > 0 {
+		statuses := make([]string, 0, len(filter.Statuses))
+		for _, status := range filter.Statuses {
+			statuses = append(statuses, string(status))
+		}
+		conds = append(conds, "p.status = ANY("+arg(statuses)+")")
+	}
+	if filter.AuthorID != "" {
+		conds = append(conds, "p.author_id = "+arg(filter.AuthorID))
+	}
+	if filter.ReviewerID != "" {
+		conds = append(conds, `EXISTS (
+		        SELECT 1
+		          FROM pull_request_reviewers rf
+		         WHERE rf.pull_request_id = p.id
+		           AND rf.user_id = `+arg(filter.ReviewerID)+`
+		   )`)
+	}
+	if filter.TeamName != "" {
+		conds = append(conds, `EXISTS (
+		        SELECT 1
+		          FROM users a
+		         WHERE a.id = p.author_id
+		           AND a.team_name = `+arg(filter.TeamName)+`
+		   )`)
+	}
+	if len(filter.Labels) > 0 {
+		labels := arg(filter.Labels)
+		conds = append(conds, `p.id IN (
+		        SELECT l.pull_request_id
+		          FROM pull_request_labels l
+		         WHERE l.label = ANY(`+labels+`)
+		         GROUP BY l.pull_request_id
+		        HAVING count(*) = cardinality(`+labels+`::text[])
+		   )`)
+	}
+	if filter.CreatedFrom != nil {
+		conds = append(conds, "p.created_at >= "+arg(*filter.CreatedFrom))
+	}
+	if filter.CreatedTo != nil {
+		conds = append(conds, "p.created_at < "+arg(*filter.CreatedTo))
+	}
+	if filter.MergedFrom != nil {
+		conds = append(conds, "p.merged_at >= "+arg(*filter.MergedFrom))
+	}
+	if filter.MergedTo != nil {
+		conds = append(conds, "p.merged_at < "+arg(*filter.MergedTo))
+	}
+	if filter.After != nil {
+		conds = append(conds, "(p.created_at, p.id) > ("+arg(filter.After.CreatedAt)+", "+arg(filter.After.ID)+")")
+	}
+	if len(conds) == 0 {
+		conds = append(conds, "true")
+	}
+
+	rows, err := s.getExecutor(ctx).Query(ctx, fmt.Sprintf(query, strings.Join(conds, "\n\t\t   AND "), arg(filter.Limit)), args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	return scanPullRequests(rows)
+}
+
+// ListUnderstaffed returns up to limit open pull requests having less reviewers
+// than max_reviewers of the author's team, least recently backfilled first,
+// never backfilled ones oldest first.
+func (s *Storage) ListUnderstaffed(ctx context.Context, limit int) ([]domain.PullRequest, error) {
+	const query = pullRequestSelect + `
+		  JOIN users a
+		    ON a.id = p.author_id
+		  JOIN team_settings ts
+		    ON ts.team_name = a.team_name
+		 WHERE p.status = ANY($1)
+		   AND cardinality(rv.reviewers) < ts.max_reviewers
+		 ORDER BY p.last_backfill_attempt_at NULLS FIRST, p.created_at, p.id
+		 LIMIT $2;
+	`
 
-		out = append(out, pullRequestDAOToDomain(dao))
+	rows, err := s.getExecutor(ctx).Query(ctx, query, openStatuses(), limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	return scanPullRequests(rows)
+}
+
+// MarkBackfillAttempted sets the backfill attempt time of the pull requests to now,
+// so the next batch starts with the ones not tried yet.
+func (s *Storage) MarkBackfillAttempted(ctx context.Context, pullRequestIDs []string) error {
+	const query = `
+		UPDATE pull_requests
+		   SET last_backfill_attempt_at = now()
+		 WHERE id = ANY($1);
+	`
+
+	_, err := s.getExecutor(ctx).Exec(ctx, query, pullRequestIDs)
+	return err
+}
+
+// ListOpenByReviewersForUpdate locks and returns open pull requests
+// where any of userIDs is a reviewer, oldest first.
+func (s *Storage) ListOpenByReviewersForUpdate(ctx context.Context, userIDs []string) ([]domain.PullRequest, error) {
+	const query = pullRequestSelect + `
+		 WHERE p.status = ANY($2)
+		   AND EXISTS (
+		        SELECT 1
+		          FROM pull_request_reviewers r
+		         WHERE r.pull_request_id = p.id
+		           AND r.user_id = ANY($1)
+		   )
+		 ORDER BY p.created_at, p.id
+		   FOR UPDATE OF p;
+	`
+
+	rows, err := s.getExecutor(ctx).Query(ctx, query, userIDs, openStatuses())
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	return scanPullRequests(rows)
+}
+
+// scanPullRequest scans a row of pullRequestSelect.
+func scanPullRequest(row pgx.Row) (domain.PullRequest, error) {
+	var dao pullRequestDAO
+	if err := row.Scan(
+		&dao.ID,
+		&dao.Name,
+		&dao.AuthorID,
+		&dao.Status,
+		&dao.CreatedAt,
+		&dao.MergedAt,
+		&dao.OverrideBy,
+		&dao.OverrideReason,
+		&dao.ClosedAt,
+		&dao.ClosedFrom,
+		&dao.Description,
+		&dao.URL,
+		&dao.Labels,
+		&dao.Reviewers,
+		&dao.Fallback,
+		&dao.Mandatory,
+		&dao.States,
+		&dao.StateUpdatedAt,
+	); err != nil {
+		return domain.PullRequest{}, err
+	}
+	return pullRequestDAOToDomain(dao), nil
+}
+
+func scanPullRequests(rows pgx.Rows) ([]domain.PullRequest, error) {
+	out := make([]domain.PullRequest, 0)
+	for rows.Next() {
+		pr, err := scanPullRequest(rows)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, pr)
 	}
 	if err := rows.Err(); err != nil {
 		return nil, err
@@ -244,3 +678,7 @@ func (s *Storage) ListByReviewer(ctx context.Context, userID string) ([]domain.P
 
 	return out, nil
 }
+
+func openStatuses() []string {
+	return []string{string(domain.PRStatusOpen), string(domain.PRStatusNeedsReviewers)}
+}
@@ -33,9 +33,20 @@ func (s *Storage) CreateWithMembers(ctx context.Context, team domain.Team) error
 		return err
 	}
 
-	const queryUser = `insert into users (id, name, team_name, is_active) values ($1, $2, $3, $4);`
+	const querySettings = `insert into team_settings (team_name) values ($1);`
+
+	_, err = s.getExecutor(ctx).Exec(ctx, querySettings, team.Name)
+	if err != nil {
+		return err
+	}
+
+	const queryUser = `insert into users (id, name, team_name, is_active, tags, seniority) values ($1, $2, $3, $4, $5, NULLIF($6, ''));`
 	for _, member := range team.Members {
-		_, err := s.getExecutor(ctx).Exec(ctx, queryUser, member.ID, member.Name, team.Name, member.IsActive)
+		tags := member.Tags
+		if tags == nil {
+			tags = []string{} // column is NOT NULL
+		}
+		_, err := s.getExecutor(ctx).Exec(ctx, queryUser, member.ID, member.Name, team.Name, member.IsActive, tags, string(member.Seniority))
 		if err != nil {
 			return err
 		}
@@ -56,7 +67,7 @@ func (s *Storage) GetWithMembers(ctx context.Context, teamName string) (*domain.
 		return nil, err
 	}
 
-	const queryUser = `select id, name, is_active from users where team_name = $1;`
+	const queryUser = `select id, name, is_active, max_open_reviews, tags, coalesce(seniority, '') from users where team_name = $1;`
 
 	rows, err := s.getExecutor(ctx).Query(ctx, queryUser, teamName)
 	if err != nil {
@@ -66,11 +77,15 @@ func (s *Storage) GetWithMembers(ctx context.Context, teamName string) (*domain.
 
 	members := make([]domain.User, 0)
 	for rows.Next() {
-		var user domain.User
-		if err := rows.Scan(&user.ID, &user.Name, &user.IsActive); err != nil {
+		var (
+			user      domain.User
+			seniority string
+		)
+		if err := rows.Scan(&user.ID, &user.Name, &user.IsActive, &user.MaxOpenReviews, &user.Tags, &seniority); err != nil {
 			return nil, err
 		}
 		user.TeamName = teamName
+		user.Seniority = domain.Seniority(seniority)
 		members = append(members, user)
 	}
 	if err := rows.Err(); err != nil {
@@ -82,3 +97,117 @@ func (s *Storage) GetWithMembers(ctx context.Context, teamName string) (*domain.
 		Members: members,
 	}, nil
 }
+
+func (s *Storage) GetTeamSettings(ctx context.Context, teamName string) (domain.TeamSettings, error) {
+	const query = `
+		SELECT team_name, min_reviewers, max_reviewers, COALESCE(strategy, ''), allow_partial, backup_teams, prefer_working_hours, mandatory_reviewers,
+		       require_senior, juniors_not_alone, required_approvals
+		  FROM team_settings
+		 WHERE team_name = $1;
+	`
+
+	var (
+		settings domain.TeamSettings
+		strategy string
+	)
+	err := s.getExecutor(ctx).QueryRow(ctx, query, teamName).Scan(
+		&settings.TeamName,
+		&settings.MinReviewers,
+		&settings.MaxReviewers,
+		&strategy,
+		&settings.AllowPartial,
+		&settings.BackupTeams,
+		&settings.PreferWorkingHours,
+		&settings.MandatoryReviewers,
+		&settings.RequireSenior,
+		&settings.JuniorsNotAlone,
+		&settings.RequiredApprovals,
+	)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return domain.TeamSettings{}, domain.ErrNotFound
+		}
+		return domain.TeamSettings{}, err
+	}
+	settings.Strategy = domain.SelectionStrategy(strategy)
+
+	return settings, nil
+}
+
+func (s *Storage) SaveTeamSettings(ctx context.Context, settings domain.TeamSettings) error {
+	backupTeams := settings.BackupTeams
+	if backupTeams == nil {
+		backupTeams = []string{} // column is NOT NULL
+	}
+	mandatoryReviewers := settings.MandatoryReviewers
+	if mandatoryReviewers == nil {
+		mandatoryReviewers = []string{}
+	}
+
+	const query = `
+		INSERT INTO team_settings (
+		    team_name, min_reviewers, max_reviewers, strategy, allow_partial,
+		    backup_teams, prefer_working_hours, mandatory_reviewers, require_senior, juniors_not_alone,
+		    required_approvals
+		) VALUES ($1, $2, $3, NULLIF($4, ''), $5, $6, $7, $8, $9, $10, $11)
+		ON CONFLICT (team_name) DO UPDATE
+		   SET min_reviewers        = EXCLUDED.min_reviewers,
+		       max_reviewers        = EXCLUDED.max_reviewers,
+		       strategy             = EXCLUDED.strategy,
+		       allow_partial        = EXCLUDED.allow_partial,
+		       backup_teams         = EXCLUDED.backup_teams,
+		       prefer_working_hours = EXCLUDED.prefer_working_hours,
+		       mandatory_reviewers  = EXCLUDED.mandatory_reviewers,
+		       require_senior       = EXCLUDED.require_senior,
+		       juniors_not_alone    = EXCLUDED.juniors_not_alone,
+		       required_approvals   = EXCLUDED.required_approvals;
+	`
+
+	_, err := s.getExecutor(ctx).Exec(ctx, query,
+		settings.TeamName,
+		settings.MinReviewers,
+		settings.MaxReviewers,
+		string(settings.Strategy),
+		settings.AllowPartial,
+		backupTeams,
+		settings.PreferWorkingHours,
+		mandatoryReviewers,
+		settings.RequireSenior,
+		settings.JuniorsNotAlone,
+		settings.RequiredApprovals,
+	)
+	return err
+}
+
+// GetCodeOwners returns empty rules when the team has no code owners.
+func (s *Storage) GetCodeOwners(ctx context.Context, teamName string) (string, error) {
+	const query = `
+		SELECT rules
+		  FROM team_code_owners
+		 WHERE team_name = $1;
+	`
+
+	var rules string
+	err := s.getExecutor(ctx).QueryRow(ctx, query, teamName).Scan(&rules)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return "", nil
+		}
+		return "", err
+	}
+
+	return rules, nil
+}
+
+func (s *Storage) SaveCodeOwners(ctx context.Context, teamName, rules string) error {
+	const query = `
+		INSERT INTO team_code_owners (team_name, rules, updated_at)
+		VALUES ($1, $2, now())
+		ON CONFLICT (team_name) DO UPDATE
+		   SET rules      = EXCLUDED.rules,
+		       updated_at = EXCLUDED.updated_at;
+	`
+
+	_, err := s.getExecutor(ctx).Exec(ctx, query, teamName, rules)
+	return err
+}
@@ -11,18 +11,12 @@ import (
 
 func (s *Storage) GetUserByID(ctx context.Context, userID string) (*domain.User, error) {
 	const query = `
-		SELECT id, name, team_name, is_active
+		SELECT id, name, team_name, is_active, max_open_reviews, timezone, work_start, work_end, tags, COALESCE(seniority, '')
 		  FROM users
 		 WHERE id = $1;
 	`
 
-	var user domain.User
-	err := s.getExecutor(ctx).QueryRow(ctx, query, userID).Scan(
-		&user.ID,
-		&user.Name,
-		&user.TeamName,
-		&user.IsActive,
-	)
+	user, err := scanUser(s.getExecutor(ctx).QueryRow(ctx, query, userID))
 	if err != nil {
 		if errors.Is(err, pgx.ErrNoRows) {
 			return nil, domain.ErrNotFound
@@ -33,6 +27,35 @@ func (s *Storage) GetUserByID(ctx context.Context, userID string) (*domain.User,
 	return &user, nil
 }
 
+// GetUsersByIDs returns users with the ids in no particular order, unknown ids are skipped.
+func (s *Storage) GetUsersByIDs(ctx context.Context, userIDs []string) ([]domain.User, error) {
+	const query = `
+		SELECT id, name, team_name, is_active, max_open_reviews, timezone, work_start, work_end, tags, COALESCE(seniority, '')
+		  FROM users
+		 WHERE id = ANY($1);
+	`
+
+	rows, err := s.getExecutor(ctx).Query(ctx, query, userIDs)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	users := make([]domain.User, 0, len(userIDs))
+	for rows.Next() {
+		user, err := scanUser(rows)
+		if err != nil {
+			return nil, err
+		}
+		users = append(users, user)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	return users, nil
+}
+
 func (s *Storage) SetIsActive(ctx context.Context, userID string, isActive bool) error {
 	const query = `
 		UPDATE users
@@ -52,12 +75,130 @@ func (s *Storage) SetIsActive(ctx context.Context, userID string, isActive bool)
 	return nil
 }
 
+// SetIsActiveMany returns ErrNotFound when any of users does not exist.
+func (s *Storage) SetIsActiveMany(ctx context.Context, userIDs []string, isActive bool) error {
+	const query = `
+		UPDATE users
+		   SET is_active = $2
+		 WHERE id = ANY($1);
+	`
+
+	cmd, err := s.getExecutor(ctx).Exec(ctx, query, userIDs, isActive)
+	if err != nil {
+		return err
+	}
+
+	if cmd.RowsAffected() != int64(len(userIDs)) {
+		return domain.ErrNotFound
+	}
+
+	return nil
+}
+
+// SetWorkingHours empty timezone and nil hours remove the setting.
+func (s *Storage) SetWorkingHours(ctx context.Context, userID, timezone string, hours *domain.WorkingHours) error {
+	const query = `
+		UPDATE users
+		   SET timezone   = NULLIF($2, ''),
+		       work_start = $3,
+		       work_end   = $4
+		 WHERE id = $1;
+	`
+
+	var start, end *int
+	if hours != nil {
+		start, end = &hours.Start, &hours.End
+	}
+
+	cmd, err := s.getExecutor(ctx).Exec(ctx, query, userID, timezone, start, end)
+	if err != nil {
+		return err
+	}
+
+	if cmd.RowsAffected() == 0 {
+		return domain.ErrNotFound
+	}
+
+	return nil
+}
+
+func (s *Storage) SetTags(ctx context.Context, userID string, tags []string) error {
+	if tags == nil {
+		tags = []string{} // column is NOT NULL
+	}
+
+	const query = `
+		UPDATE users
+		   SET tags = $2
+		 WHERE id = $1;
+	`
+
+	cmd, err := s.getExecutor(ctx).Exec(ctx, query, userID, tags)
+	if err != nil {
+		return err
+	}
+
+	if cmd.RowsAffected() == 0 {
+		return domain.ErrNotFound
+	}
+
+	return nil
+}
+
+func (s *Storage) SetSeniority(ctx context.Context, userID string, seniority domain.Seniority) error {
+	const query = `
+		UPDATE users
+		   SET seniority = NULLIF($2, '')
+		 WHERE id = $1;
+	`
+
+	cmd, err := s.getExecutor(ctx).Exec(ctx, query, userID, string(seniority))
+	if err != nil {
+		return err
+	}
+
+	if cmd.RowsAffected() == 0 {
+		return domain.ErrNotFound
+	}
+
+	return nil
+}
+
+// SetMaxOpenReviews nil removes the limit.
+func (s *Storage) SetMaxOpenReviews(ctx context.Context, userID string, maxOpenReviews *int) error {
+	const query = `
+		UPDATE users
+		   SET max_open_reviews = $2
+		 WHERE id = $1;
+	`
+
+	cmd, err := s.getExecutor(ctx).Exec(ctx, query, userID, maxOpenReviews)
+	if err != nil {
+		return err
+	}
+
+	if cmd.RowsAffected() == 0 {
+		return domain.ErrNotFound
+	}
+
+	return nil
+}
+
+// ListActiveUserByTeam users with absence covering current UTC date are not returned.
 func (s *Storage) ListActiveUserByTeam(ctx context.Context, teamName string) ([]domain.User, error) {
 	const query = `
-		SELECT id, name, team_name, is_active
-		  FROM users
-		 WHERE team_name = $1
-		   AND is_active = true;
+		SELECT u.id, u.name, u.team_name, u.is_active, u.max_open_reviews, u.timezone, u.work_start, u.work_end, u.tags,
+		       COALESCE(u.seniority, '')
+		  FROM users u
+		 WHERE u.team_name = $1
+		   AND u.is_active = true
+		   AND NOT EXISTS (
+		        SELECT 1
+		          FROM user_absences a
+		         WHERE a.user_id = u.id
+		           AND a.cancelled_at IS NULL
+		           AND (now() AT TIME ZONE 'UTC')::date BETWEEN a.start_date AND a.end_date
+		   );
 	`
 
 	rows, err := s.getExecutor(ctx).Query(ctx, query, teamName)
@@ -68,13 +209,8 @@ func (s *Storage) ListActiveUserByTeam(ctx context.Context, teamName string) ([]
 
 	users := make([]domain.User, 0)
 	for rows.Next() {
-		var user domain.User
-		if err := rows.Scan(
-			&user.ID,
-			&user.Name,
-			&user.TeamName,
-			&user.IsActive,
-		); err != nil {
+		user, err := scanUser(rows)
+		if err != nil {
 			return nil, err
 		}
 		users = append(users, user)
@@ -85,3 +221,36 @@ func (s *Storage) ListActiveUserByTeam(ctx context.Context, teamName string) ([]
 
 	return users, nil
 }
+
+// scanUser scans id, name, team_name, is_active, max_open_reviews, timezone, work_start, work_end, tags, seniority.
+func scanUser(row pgx.Row) (domain.User, error) {
+	var (
+		user       domain.User
+		timezone   *string
+		start, end *int
+		seniority  string
+	)
+	if err := row.Scan(
+		&user.ID,
+		&user.Name,
+		&user.TeamName,
+		&user.IsActive,
+		&user.MaxOpenReviews,
+		&timezone,
+		&start,
+		&end,
+		&user.Tags,
+		&seniority,
+	); err != nil {
+		return domain.User{}, err
+	}
+
+	user.Seniority = domain.Seniority(seniority)
+	if timezone != nil {
+		user.Timezone = *timezone
+	}
+	if start != nil && end != nil {
+		user.WorkingHours = &domain.WorkingHours{Start: *start, End: *end}
+	}
+	return user, nil
+}
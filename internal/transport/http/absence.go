@@ -0,0 +1,101 @@
+package http
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"avito/internal/domain"
+)
+
+func (h *Handler) handleAbsenceAdd(w http.ResponseWriter, r *http.Request) {
+	var req AbsenceAddRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeJSON(w, http.StatusBadRequest, ErrorResponse{
+			Error: errorBody{
+				Code:    "BAD_REQUEST",
+				Message: "invalid JSON",
+			},
+		})
+		return
+	}
+
+	startDate, errStart := time.Parse(dateLayout, req.StartDate)
+	endDate, errEnd := time.Parse(dateLayout, req.EndDate)
+	if errStart != nil || errEnd != nil {
+		writeJSON(w, http.StatusBadRequest, ErrorResponse{
+			Error: errorBody{
+				Code:    "BAD_REQUEST",
+				Message: "start_date and end_date must be YYYY-MM-DD",
+			},
+		})
+		return
+	}
+
+	absence, err := h.usersService.AddAbsence(r.Context(), domain.Absence{
+		UserID:    req.UserID,
+		StartDate: startDate,
+		EndDate:   endDate,
+		Reason:    req.Reason,
+	})
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusCreated, AbsenceResponse{
+		Absence: absenceToDto(absence),
+	})
+}
+
+func (h *Handler) handleAbsenceList(w http.ResponseWriter, r *http.Request) {
+	userID := r.URL.Query().Get("user_id")
+	if userID == "" {
+		writeJSON(w, http.StatusBadRequest, ErrorResponse{
+			Error: errorBody{
+				Code:    "BAD_REQUEST",
+				Message: "user_id is required",
+			},
+		})
+		return
+	}
+
+	absences, err := h.usersService.ListAbsences(r.Context(), userID)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	resp := AbsenceListResponse{
+		UserID:   userID,
+		Absences: make([]AbsenceDTO, 0, len(absences)),
+	}
+	for _, absence := range absences {
+		resp.Absences = append(resp.Absences, absenceToDto(absence))
+	}
+
+	writeJSON(w, http.StatusOK, resp)
+}
+
+func (h *Handler) handleAbsenceCancel(w http.ResponseWriter, r *http.Request) {
+	var req AbsenceCancelRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeJSON(w, http.StatusBadRequest, ErrorResponse{
+			Error: errorBody{
+				Code:    "BAD_REQUEST",
+				Message: "invalid JSON",
+			},
+		})
+		return
+	}
+
+	absence, err := h.usersService.CancelAbsence(r.Context(), req.ID)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, AbsenceResponse{
+		Absence: absenceToDto(absence),
+	})
+}
@@ -1,8 +1,12 @@
 package http
 
 import (
+	"encoding/base64"
 	"errors"
 	"net/http"
+	"strconv"
+	"strings"
+	"time"
 
 	"avito/internal/domain"
 )
@@ -11,10 +15,12 @@ func teamFromDto(team TeamDTO) domain.Team {
 	members := make([]domain.User, len(team.Members))
 	for i, member := range team.Members {
 		members[i] = domain.User{
-			ID:       member.UserID,
-			Name:     member.Username,
-			TeamName: team.TeamName,
-			IsActive: member.IsActive,
+			ID:        member.UserID,
+			Name:      member.Username,
+			TeamName:  team.TeamName,
+			IsActive:  member.IsActive,
+			Tags:      member.Tags,
+			Seniority: domain.Seniority(member.Seniority),
 		}
 	}
 	return domain.Team{
@@ -27,9 +33,11 @@ func teamToDto(t *domain.Team) TeamDTO {
 	members := make([]TeamMemberDTO, 0, len(t.Members))
 	for _, member := range t.Members {
 		members = append(members, TeamMemberDTO{
-			UserID:   member.ID,
-			Username: member.Name,
-			IsActive: member.IsActive,
+			UserID:    member.ID,
+			Username:  member.Name,
+			IsActive:  member.IsActive,
+			Tags:      member.Tags,
+			Seniority: string(member.Seniority),
 		})
 	}
 
@@ -39,12 +47,153 @@ func teamToDto(t *domain.Team) TeamDTO {
 	}
 }
 
+func teamSettingsToDto(settings domain.TeamSettings) TeamSettingsDTO {
+	backupTeams := make([]string, len(settings.BackupTeams))
+	copy(backupTeams, settings.BackupTeams)
+	mandatoryReviewers := make([]string, len(settings.MandatoryReviewers))
+	copy(mandatoryReviewers, settings.MandatoryReviewers)
+	return TeamSettingsDTO{
+		TeamName:     settings.TeamName,
+		MinReviewers: settings.MinReviewers,
+		MaxReviewers: settings.MaxReviewers,
+		Strategy:     string(settings.Strategy),
+		AllowPartial: settings.AllowPartial,
+		BackupTeams:  backupTeams,
+
+		PreferWorkingHours: settings.PreferWorkingHours,
+		MandatoryReviewers: mandatoryReviewers,
+		RequireSenior:      settings.RequireSenior,
+		JuniorsNotAlone:    settings.JuniorsNotAlone,
+		RequiredApprovals:  settings.RequiredApprovals,
+	}
+}
+
+func teamSettingsUpdateFromDto(req TeamSettingsSetRequest) domain.TeamSettingsUpdate {
+	update := domain.TeamSettingsUpdate{
+		MinReviewers: req.MinReviewers,
+		MaxReviewers: req.MaxReviewers,
+		AllowPartial: req.AllowPartial,
+		BackupTeams:  req.BackupTeams,
+
+		PreferWorkingHours: req.PreferWorkingHours,
+		MandatoryReviewers: req.MandatoryReviewers,
+		RequireSenior:      req.RequireSenior,
+		JuniorsNotAlone:    req.JuniorsNotAlone,
+		RequiredApprovals:  req.RequiredApprovals,
+	}
+	if req.Strategy != nil {
+		strategy := domain.SelectionStrategy(*req.Strategy)
+		update.Strategy = &strategy
+	}
+	return update
+}
+
+func codeOwnersToDto(teamName string, rules []domain.CodeOwnersRule) TeamCodeOwnersDTO {
+	out := TeamCodeOwnersDTO{
+		TeamName: teamName,
+		Rules:    make([]CodeOwnersRuleDTO, 0, len(rules)),
+	}
+	for _, rule := range rules {
+		users := make([]string, len(rule.Users))
+		copy(users, rule.Users)
+		teams := make([]string, len(rule.Teams))
+		copy(teams, rule.Teams)
+		out.Rules = append(out.Rules, CodeOwnersRuleDTO{
+			Pattern: rule.Pattern,
+			Users:   users,
+			Teams:   teams,
+		})
+	}
+	return out
+}
+
 func userToDto(user *domain.User) UserDTO {
-	return UserDTO{
-		UserID:   user.ID,
-		Username: user.Name,
-		IsActive: user.IsActive,
-		TeamName: user.TeamName,
+	dto := UserDTO{
+		UserID:         user.ID,
+		Username:       user.Name,
+		IsActive:       user.IsActive,
+		TeamName:       user.TeamName,
+		MaxOpenReviews: user.MaxOpenReviews,
+		Timezone:       user.Timezone,
+		Tags:           user.Tags,
+		Seniority:      string(user.Seniority),
+	}
+	if user.WorkingHours != nil {
+		dto.WorkStart = formatClock(user.WorkingHours.Start)
+		dto.WorkEnd = formatClock(user.WorkingHours.End)
+	}
+	return dto
+}
+
+const clockLayout = "15:04"
+
+// parseClock parses "HH:MM" to minutes from midnight.
+func parseClock(value string) (int, error) {
+	t, err := time.Parse(clockLayout, value)
+	if err != nil {
+		return 0, err
+	}
+	return t.Hour()*60 + t.Minute(), nil
+}
+
+func formatClock(minutes int) string {
+	return time.Date(0, 1, 1, 0, minutes, 0, 0, time.UTC).Format(clockLayout)
+}
+
+const dateLayout = "2006-01-02"
+
+// parseTimeParam accepts RFC 3339 time or YYYY-MM-DD meaning midnight UTC, empty value is nil.
+func parseTimeParam(value string) (*time.Time, error) {
+	if value == "" {
+		return nil, nil
+	}
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		t, err = time.Parse(dateLayout, value)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &t, nil
+}
+
+// encodeCursor opaque cursor of the next page.
+func encodeCursor(cursor *domain.PullRequestCursor) string {
+	if cursor == nil {
+		return ""
+	}
+	raw := cursor.CreatedAt.UTC().Format(time.RFC3339Nano) + "|" + cursor.ID
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+func decodeCursor(value string) (*domain.PullRequestCursor, error) {
+	if value == "" {
+		return nil, nil
+	}
+	raw, err := base64.RawURLEncoding.DecodeString(value)
+	if err != nil {
+		return nil, err
+	}
+	createdAt, id, ok := strings.Cut(string(raw), "|")
+	if !ok {
+		return nil, errors.New("cursor without id")
+	}
+	t, err := time.Parse(time.RFC3339Nano, createdAt)
+	if err != nil {
+		return nil, err
+	}
+	return &domain.PullRequestCursor{CreatedAt: t, ID: id}, nil
+}
+
+func absenceToDto(absence domain.Absence) AbsenceDTO {
+	return AbsenceDTO{
+		ID:          absence.ID,
+		UserID:      absence.UserID,
+		StartDate:   absence.StartDate.Format(dateLayout),
+		EndDate:     absence.EndDate.Format(dateLayout),
+		Reason:      absence.Reason,
+		CreatedAt:   absence.CreatedAt,
+		CancelledAt: absence.CancelledAt,
 	}
 }
 
@@ -58,19 +207,178 @@ func pullRequestShortToDto(pr domain.PullRequest) PullRequestShortDTO {
 }
 
 func pullRequestToDto(pr domain.PullRequest) PullRequestDTO {
-	reviewers := make([]string, len(pr.AssignedReviewers))
-	copy(reviewers, pr.AssignedReviewers)
+	reviewers := make([]AssignedReviewerDTO, 0, len(pr.AssignedReviewers))
+	for _, id := range pr.AssignedReviewers {
+		review := pr.ReviewOf(id)
+		reviewers = append(reviewers, AssignedReviewerDTO{
+			UserID:    id,
+			State:     string(review.State),
+			UpdatedAt: review.UpdatedAt,
+		})
+	}
+	fallback := make([]string, len(pr.FallbackReviewers))
+	copy(fallback, pr.FallbackReviewers)
+	mandatory := make([]string, len(pr.MandatoryReviewers))
+	copy(mandatory, pr.MandatoryReviewers)
+	labels := make([]string, len(pr.Labels))
+	copy(labels, pr.Labels)
+	var override *MergeOverrideDTO
+	if pr.MergeOverride != nil {
+		override = &MergeOverrideDTO{
+			By:     pr.MergeOverride.By,
+			Reason: pr.MergeOverride.Reason,
+		}
+	}
 	return PullRequestDTO{
-		ID:                pr.ID,
-		Name:              pr.Name,
-		AuthorID:          pr.AuthorID,
-		Status:            string(pr.Status),
-		AssignedReviewers: reviewers,
-		CreatedAt:         pr.CreatedAt,
-		MergedAt:          pr.MergedAt,
+		ID:                 pr.ID,
+		Name:               pr.Name,
+		Description:        pr.Description,
+		URL:                pr.URL,
+		Labels:             labels,
+		AuthorID:           pr.AuthorID,
+		Status:             string(pr.Status),
+		AssignedReviewers:  reviewers,
+		FallbackReviewers:  fallback,
+		CreatedAt:          pr.CreatedAt,
+		MandatoryReviewers: mandatory,
+		MergedAt:           pr.MergedAt,
+		MergeOverride:      override,
+		ClosedAt:           pr.ClosedAt,
+	}
+}
+
+// prCreateToDto reviewers are reported in the assignment order.
+func prCreateToDto(pr domain.PullRequest) PRCreateResponse {
+	resp := PRCreateResponse{
+		PR: pullRequestToDto(pr),
+	}
+	if len(pr.RequiredTags) == 0 {
+		return resp
+	}
+
+	resp.TagMatches = make([]ReviewerTagsDTO, 0, len(pr.MatchedTags))
+	resp.UncoveredTags = make([]string, 0)
+	covered := make(map[string]bool)
+	for _, id := range pr.AssignedReviewers {
+		tags, ok := pr.MatchedTags[id]
+		if !ok {
+			continue
+		}
+		resp.TagMatches = append(resp.TagMatches, ReviewerTagsDTO{ReviewerID: id, Tags: tags})
+		for _, tag := range tags {
+			covered[tag] = true
+		}
+	}
+	for _, tag := range pr.RequiredTags {
+		if !covered[tag] {
+			resp.UncoveredTags = append(resp.UncoveredTags, tag)
+		}
+	}
+	return resp
+}
+
+func prPreviewToDto(preview domain.PullRequestPreview) PRPreviewResponse {
+	resp := PRPreviewResponse{
+		PRCreateResponse: prCreateToDto(preview.PullRequest),
+		EligiblePool:     make([]string, 0),
+		Decision:         assignmentDecisionToDto(preview.Decision),
+	}
+	for _, team := range preview.Decision.Teams {
+		resp.EligiblePool = append(resp.EligiblePool, team.Eligible...)
+	}
+	return resp
+}
+
+func backfillToDto(backfill domain.Backfill) PRBackfillDTO {
+	added := make([]string, len(backfill.AddedReviewers))
+	copy(added, backfill.AddedReviewers)
+	dto := PRBackfillDTO{
+		PR:             pullRequestToDto(backfill.PullRequest),
+		AddedReviewers: added,
+	}
+	if backfill.Err != nil {
+		_, resp := mappingDomainErrors(backfill.Err)
+		dto.Error = &resp.Error
+	}
+	return dto
+}
+
+func assignmentAuditToDto(audit domain.AssignmentAudit) AssignmentAuditDTO {
+	steps := make([]SelectionStepDTO, 0, len(audit.Steps))
+	for _, step := range audit.Steps {
+		candidates := make([]string, len(step.Candidates))
+		copy(candidates, step.Candidates)
+		chosen := make([]string, len(step.Chosen))
+		copy(chosen, step.Chosen)
+		steps = append(steps, SelectionStepDTO{
+			TeamName:   step.TeamName,
+			Strategy:   string(step.Strategy),
+			Candidates: candidates,
+			Quantity:   step.Quantity,
+			Chosen:     chosen,
+			Inputs:     (*SelectionInputsDTO)(step.Inputs),
+		})
+	}
+	return AssignmentAuditDTO{
+		Action:    string(audit.Action),
+		Seed:      strconv.FormatUint(audit.Seed, 10),
+		Steps:     steps,
+		CreatedAt: audit.CreatedAt,
+	}
+}
+
+func assignmentDecisionToDto(decision domain.AssignmentDecision) AssignmentDecisionDTO {
+	teams := make([]TeamCandidatesDTO, 0, len(decision.Teams))
+	for _, team := range decision.Teams {
+		eligible := make([]string, len(team.Eligible))
+		copy(eligible, team.Eligible)
+		excluded := make([]ExclusionDTO, 0, len(team.Excluded))
+		for _, exclusion := range team.Excluded {
+			excluded = append(excluded, ExclusionDTO{
+				UserID: exclusion.UserID,
+				Reason: string(exclusion.Reason),
+			})
+		}
+		teams = append(teams, TeamCandidatesDTO{
+			TeamName: team.TeamName,
+			Eligible: eligible,
+			Excluded: excluded,
+		})
+	}
+
+	reviewers := make([]ChosenReviewerDTO, 0, len(decision.Reviewers))
+	for _, reviewer := range decision.Reviewers {
+		reviewers = append(reviewers, ChosenReviewerDTO{
+			UserID:     reviewer.UserID,
+			TeamName:   reviewer.TeamName,
+			Reason:     string(reviewer.Reason),
+			IsFallback: reviewer.IsFallback,
+		})
+	}
+
+	return AssignmentDecisionDTO{
+		Action:     string(decision.Action),
+		Strategy:   string(decision.Strategy),
+		Candidates: teams,
+		Reviewers:  reviewers,
+		CreatedAt:  decision.CreatedAt,
 	}
 }
 
+func reassignmentsToDto(reassignments []domain.Reassignment) []ReassignmentDTO {
+	out := make([]ReassignmentDTO, 0, len(reassignments))
+	for _, reassignment := range reassignments {
+		out = append(out, ReassignmentDTO{
+			PullRequestID: reassignment.PullRequestID,
+			OldUserID:     reassignment.OldReviewerID,
+			ReplacedBy:    reassignment.NewReviewerID,
+			NoCandidate:   reassignment.NewReviewerID == "",
+			IsFallback:    reassignment.IsFallback,
+		})
+	}
+	return out
+}
+
 func mappingDomainErrors(err error) (int, ErrorResponse) {
 	var code string
 	var status int
@@ -92,14 +400,42 @@ func mappingDomainErrors(err error) (int, ErrorResponse) {
 		status = http.StatusConflict
 		code = "NOT_ASSIGNED"
 
+	case errors.Is(err, domain.ErrInvalidStatus):
+		status = http.StatusConflict
+		code = "INVALID_STATUS"
+
+	case errors.Is(err, domain.ErrNotApproved):
+		status = http.StatusConflict
+		code = "NOT_APPROVED"
+
+	case errors.Is(err, domain.ErrAlreadyAssigned):
+		status = http.StatusConflict
+		code = "ALREADY_ASSIGNED"
+
+	case errors.Is(err, domain.ErrReviewerUnavailable):
+		status = http.StatusConflict
+		code = "REVIEWER_UNAVAILABLE"
+
 	case errors.Is(err, domain.ErrNoCandidate):
 		status = http.StatusConflict
 		code = "NO_CANDIDATE"
 
+	case errors.Is(err, domain.ErrMandatoryReviewer):
+		status = http.StatusConflict
+		code = "MANDATORY_REVIEWER"
+
+	case errors.Is(err, domain.ErrSeniorityPolicy):
+		status = http.StatusConflict
+		code = "SENIORITY_POLICY"
+
 	case errors.Is(err, domain.ErrNotFound):
 		status = http.StatusNotFound
 		code = "NOT_FOUND"
 
+	case errors.Is(err, domain.ErrInvalidInput):
+		status = http.StatusBadRequest
+		code = "BAD_REQUEST"
+
 	default:
 		status = http.StatusInternalServerError
 		code = "INTERNAL"
@@ -11,10 +11,13 @@ type ErrorResponse struct {
 	Error errorBody `json:"error"`
 }
 
+// TeamMemberDTO seniority is JUNIOR, MIDDLE, SENIOR or LEAD in any case, empty is unknown.
 type TeamMemberDTO struct {
-	UserID   string `json:"user_id"`
-	Username string `json:"username"`
-	IsActive bool   `json:"is_active"`
+	UserID    string   `json:"user_id"`
+	Username  string   `json:"username"`
+	IsActive  bool     `json:"is_active"`
+	Tags      []string `json:"tags,omitempty"`
+	Seniority string   `json:"seniority,omitempty"`
 }
 
 type TeamDTO struct {
@@ -26,11 +29,101 @@ type TeamAddResponse struct {
 	Team TeamDTO `json:"team"`
 }
 
+type TeamSettingsDTO struct {
+	TeamName     string   `json:"team_name"`
+	MinReviewers int      `json:"min_reviewers"`
+	MaxReviewers int      `json:"max_reviewers"`
+	Strategy     string   `json:"strategy,omitempty"`
+	AllowPartial bool     `json:"allow_partial"`
+	BackupTeams  []string `json:"backup_teams"`
+
+	PreferWorkingHours bool     `json:"prefer_working_hours"`
+	MandatoryReviewers []string `json:"mandatory_reviewers"`
+	RequireSenior      bool     `json:"require_senior"`
+	JuniorsNotAlone    bool     `json:"juniors_not_alone"`
+	RequiredApprovals  int      `json:"required_approvals"`
+}
+
+type TeamSettingsSetRequest struct {
+	TeamName     string    `json:"team_name"`
+	MinReviewers *int      `json:"min_reviewers,omitempty"`
+	MaxReviewers *int      `json:"max_reviewers,omitempty"`
+	Strategy     *string   `json:"strategy,omitempty"`
+	AllowPartial *bool     `json:"allow_partial,omitempty"`
+	BackupTeams  *[]string `json:"backup_teams,omitempty"`
+
+	PreferWorkingHours *bool     `json:"prefer_working_hours,omitempty"`
+	MandatoryReviewers *[]string `json:"mandatory_reviewers,omitempty"`
+	RequireSenior      *bool     `json:"require_senior,omitempty"`
+	JuniorsNotAlone    *bool     `json:"juniors_not_alone,omitempty"`
+	RequiredApprovals  *int      `json:"required_approvals,omitempty"`
+}
+
+type TeamSettingsSetResponse struct {
+	Settings TeamSettingsDTO `json:"settings"`
+}
+
+type CodeOwnersRuleDTO struct {
+	Pattern string   `json:"pattern"`
+	Users   []string `json:"users"`
+	Teams   []string `json:"teams"`
+}
+
+type TeamCodeOwnersDTO struct {
+	TeamName string              `json:"team_name"`
+	Rules    []CodeOwnersRuleDTO `json:"rules"`
+}
+
+// TeamCodeOwnersSetRequest codeowners is the rules file: "pattern owner..." per line,
+// owners are user ids or "team:<team_name>".
+type TeamCodeOwnersSetRequest struct {
+	TeamName   string `json:"team_name"`
+	CodeOwners string `json:"codeowners"`
+}
+
+type TeamSetMemberTagsRequest struct {
+	TeamName string   `json:"team_name"`
+	UserID   string   `json:"user_id"`
+	Tags     []string `json:"tags"`
+}
+
+type TeamSetMemberTagsResponse struct {
+	User UserDTO `json:"user"`
+}
+
+// TeamSetMemberSeniorityRequest empty seniority makes it unknown.
+type TeamSetMemberSeniorityRequest struct {
+	TeamName  string `json:"team_name"`
+	UserID    string `json:"user_id"`
+	Seniority string `json:"seniority"`
+}
+
+type TeamSetMemberSeniorityResponse struct {
+	User UserDTO `json:"user"`
+}
+
+type TeamDeactivateUsersRequest struct {
+	TeamName string   `json:"team_name"`
+	UserIDs  []string `json:"user_ids"`
+}
+
+type TeamDeactivateUsersResponse struct {
+	TeamName      string            `json:"team_name"`
+	Users         []UserDTO         `json:"users"`
+	Reassignments []ReassignmentDTO `json:"reassignments"`
+}
+
 type UserDTO struct {
-	UserID   string `json:"user_id"`
-	Username string `json:"username"`
-	TeamName string `json:"team_name"`
-	IsActive bool   `json:"is_active"`
+	UserID         string   `json:"user_id"`
+	Username       string   `json:"username"`
+	TeamName       string   `json:"team_name"`
+	IsActive       bool     `json:"is_active"`
+	MaxOpenReviews *int     `json:"max_open_reviews,omitempty"`
+	Timezone       string   `json:"timezone,omitempty"`
+	WorkStart      string   `json:"work_start,omitempty"`
+	WorkEnd        string   `json:"work_end,omitempty"`
+	Tags           []string `json:"tags,omitempty"`
+	Seniority      string   `json:"seniority,omitempty"`
 }
 
 type UserSetIsActiveRequest struct {
@@ -38,23 +131,107 @@ type UserSetIsActiveRequest struct {
 	IsActive bool   `json:"is_active"`
 }
 
+// ReassignmentDTO replaced_by is empty when there was no candidate.
+type ReassignmentDTO struct {
+	PullRequestID string `json:"pull_request_id"`
+	OldUserID     string `json:"old_user_id"`
+	ReplacedBy    string `json:"replaced_by,omitempty"`
+	NoCandidate   bool   `json:"no_candidate"`
+	IsFallback    bool   `json:"is_fallback"`
+}
+
 type UserSetIsActiveResponse struct {
+	User          UserDTO           `json:"user"`
+	Reassignments []ReassignmentDTO `json:"reassignments,omitempty"`
+}
+
+// UserSetCapacityRequest null max_open_reviews removes the limit.
+type UserSetCapacityRequest struct {
+	UserID         string `json:"user_id"`
+	MaxOpenReviews *int   `json:"max_open_reviews"`
+}
+
+type UserSetCapacityResponse struct {
 	User UserDTO `json:"user"`
 }
 
+// UserSetWorkingHoursRequest work_start and work_end are "HH:MM" in the timezone,
+// both must be set or both omitted; omitted ones make the user always available.
+type UserSetWorkingHoursRequest struct {
+	UserID    string  `json:"user_id"`
+	Timezone  string  `json:"timezone"`
+	WorkStart *string `json:"work_start"`
+	WorkEnd   *string `json:"work_end"`
+}
+
+type UserSetWorkingHoursResponse struct {
+	User UserDTO `json:"user"`
+}
+
+// AbsenceDTO dates are "YYYY-MM-DD", both inclusive.
+type AbsenceDTO struct {
+	ID          int64      `json:"absence_id"`
+	UserID      string     `json:"user_id"`
+	StartDate   string     `json:"start_date"`
+	EndDate     string     `json:"end_date"`
+	Reason      string     `json:"reason"`
+	CreatedAt   time.Time  `json:"createdAt"`
+	CancelledAt *time.Time `json:"cancelledAt,omitempty"`
+}
+
+type AbsenceAddRequest struct {
+	UserID    string `json:"user_id"`
+	StartDate string `json:"start_date"`
+	EndDate   string `json:"end_date"`
+	Reason    string `json:"reason"`
+}
+
+type AbsenceResponse struct {
+	Absence AbsenceDTO `json:"absence"`
+}
+
+type AbsenceListResponse struct {
+	UserID   string       `json:"user_id"`
+	Absences []AbsenceDTO `json:"absences"`
+}
+
+type AbsenceCancelRequest struct {
+	ID int64 `json:"absence_id"`
+}
+
 type UsersGetReviewResponse struct {
 	UserID       string                `json:"user_id"`
 	PullRequests []PullRequestShortDTO `json:"pull_requests"`
+	NextCursor   string                `json:"next_cursor,omitempty"`
 }
 
 type PullRequestDTO struct {
-	ID                string     `json:"pull_request_id"`
-	Name              string     `json:"pull_request_name"`
-	AuthorID          string     `json:"author_id"`
-	Status            string     `json:"status"`
-	AssignedReviewers []string   `json:"assigned_reviewers"`
-	CreatedAt         *time.Time `json:"createdAt,omitempty"`
-	MergedAt          *time.Time `json:"mergedAt,omitempty"`
+	ID                 string                `json:"pull_request_id"`
+	Name               string                `json:"pull_request_name"`
+	Description        string                `json:"description,omitempty"`
+	URL                string                `json:"url,omitempty"`
+	Labels             []string              `json:"labels,omitempty"`
+	AuthorID           string                `json:"author_id"`
+	Status             string                `json:"status"`
+	AssignedReviewers  []AssignedReviewerDTO `json:"assigned_reviewers"`
+	FallbackReviewers  []string              `json:"fallback_reviewers,omitempty"`
+	MandatoryReviewers []string              `json:"mandatory_reviewers,omitempty"`
+	CreatedAt          *time.Time            `json:"createdAt,omitempty"`
+	MergedAt           *time.Time            `json:"mergedAt,omitempty"`
+	MergeOverride      *MergeOverrideDTO     `json:"merge_override,omitempty"`
+	ClosedAt           *time.Time            `json:"closedAt,omitempty"`
+}
+
+type MergeOverrideDTO struct {
+	By     string `json:"by"`
+	Reason string `json:"reason,omitempty"`
+}
+
+// AssignedReviewerDTO updatedAt is the time of the last verdict, absent while PENDING.
+type AssignedReviewerDTO struct {
+	UserID    string     `json:"user_id"`
+	State     string     `json:"state"`
+	UpdatedAt *time.Time `json:"updatedAt,omitempty"`
 }
 
 type PullRequestShortDTO struct {
@@ -64,30 +241,190 @@ type PullRequestShortDTO struct {
 	Status   string `json:"status"`
 }
 
+// PRCreateRequest draft PR gets reviewers on /pullRequest/markReady, preview ignores draft.
 type PRCreateRequest struct {
-	ID     string `json:"pull_request_id"`
-	Name   string `json:"pull_request_name"`
-	Author string `json:"author_id"`
+	ID           string   `json:"pull_request_id"`
+	Name         string   `json:"pull_request_name"`
+	Author       string   `json:"author_id"`
+	ChangedFiles []string `json:"changed_files,omitempty"`
+	RequiredTags []string `json:"required_tags,omitempty"`
+	Draft        bool     `json:"draft,omitempty"`
+}
+
+// PRUpdateRequest absent fields are left unchanged, labels replace all labels.
+type PRUpdateRequest struct {
+	PullRequestID string    `json:"pull_request_id"`
+	Name          *string   `json:"pull_request_name,omitempty"`
+	Description   *string   `json:"description,omitempty"`
+	URL           *string   `json:"url,omitempty"`
+	Labels        *[]string `json:"labels,omitempty"`
+}
+
+// PRListResponse next_cursor is absent on the last page.
+type PRListResponse struct {
+	PullRequests []PullRequestDTO `json:"pull_requests"`
+	NextCursor   string           `json:"next_cursor,omitempty"`
+}
+
+// PRStatusRequest body of /pullRequest/close and /pullRequest/reopen.
+type PRStatusRequest struct {
+	PullRequestID string `json:"pull_request_id"`
+}
+
+// PRMarkReadyRequest changed_files and required_tags are used like in /pullRequest/create.
+type PRMarkReadyRequest struct {
+	PullRequestID string   `json:"pull_request_id"`
+	ChangedFiles  []string `json:"changed_files,omitempty"`
+	RequiredTags  []string `json:"required_tags,omitempty"`
+}
+
+// ReviewerTagsDTO required tags the reviewer has.
+type ReviewerTagsDTO struct {
+	ReviewerID string   `json:"reviewer_id"`
+	Tags       []string `json:"tags"`
 }
 
+// PRCreateResponse tag_matches and uncovered_tags are set when required_tags were requested.
 type PRCreateResponse struct {
-	PR PullRequestDTO `json:"pr"`
+	PR            PullRequestDTO    `json:"pr"`
+	TagMatches    []ReviewerTagsDTO `json:"tag_matches,omitempty"`
+	UncoveredTags []string          `json:"uncovered_tags,omitempty"`
+}
+
+// PRPreviewResponse eligible_pool is everyone who could be chosen, chosen reviewers included.
+type PRPreviewResponse struct {
+	PRCreateResponse
+	EligiblePool []string              `json:"eligible_pool"`
+	Decision     AssignmentDecisionDTO `json:"decision"`
 }
 
+// PRMergeRequest override merges PR without approvals required by the team and is allowed
+// to admins only, see Handler.isAdmin. override_by is required with it and is recorded as is.
 type PRMergeRequest struct {
-	ID string `json:"pull_request_id"`
+	ID             string `json:"pull_request_id"`
+	Override       bool   `json:"override,omitempty"`
+	OverrideBy     string `json:"override_by,omitempty"`
+	OverrideReason string `json:"override_reason,omitempty"`
 }
 
 type PRMergeResponse struct {
 	PR PullRequestDTO `json:"pr"`
 }
 
+// PRReassignRequest force allows to replace a mandatory reviewer,
+// new_user_id chooses the replacement instead of the team strategy.
 type PRReassignRequest struct {
 	PullRequestID string `json:"pull_request_id"`
 	OldUserID     string `json:"old_user_id"`
+	NewUserID     string `json:"new_user_id,omitempty"`
+	Force         bool   `json:"force,omitempty"`
 }
 
 type PRReassignResponse struct {
 	PR         PullRequestDTO `json:"pr"`
 	ReplacedBy string         `json:"replaced_by"`
 }
+
+type PRAddReviewerRequest struct {
+	PullRequestID string `json:"pull_request_id"`
+	UserID        string `json:"user_id"`
+}
+
+// PRReviewRequest state is APPROVED, CHANGES_REQUESTED or COMMENTED.
+type PRReviewRequest struct {
+	PullRequestID string `json:"pull_request_id"`
+	UserID        string `json:"user_id"`
+	State         string `json:"state"`
+}
+
+// PRRemoveReviewerRequest force allows to remove a mandatory reviewer.
+type PRRemoveReviewerRequest struct {
+	PullRequestID string `json:"pull_request_id"`
+	UserID        string `json:"user_id"`
+	Force         bool   `json:"force,omitempty"`
+}
+
+// PRResponse answer of endpoints changing one PR.
+type PRResponse struct {
+	PR PullRequestDTO `json:"pr"`
+}
+
+// PRBackfillRequest empty body or pull_request_id means all understaffed PRs.
+type PRBackfillRequest struct {
+	PullRequestID string `json:"pull_request_id"`
+}
+
+// PRBackfillDTO Error is set when the PR could not be topped up in the batch mode.
+type PRBackfillDTO struct {
+	PR             PullRequestDTO `json:"pr"`
+	AddedReviewers []string       `json:"added_reviewers"`
+	Error          *errorBody     `json:"error,omitempty"`
+}
+
+type PRBackfillResponse struct {
+	PullRequests []PRBackfillDTO `json:"pull_requests"`
+}
+
+// SelectionStepDTO Replayed is filled only when replay is requested.
+type SelectionStepDTO struct {
+	TeamName   string              `json:"team_name"`
+	Strategy   string              `json:"strategy"`
+	Candidates []string            `json:"candidates"`
+	Quantity   int                 `json:"quantity"`
+	Chosen     []string            `json:"chosen"`
+	Inputs     *SelectionInputsDTO `json:"inputs,omitempty"`
+	Replayed   []string            `json:"replayed,omitempty"`
+}
+
+// SelectionInputsDTO outside state the strategy used, only the fields of the strategy are set.
+type SelectionInputsDTO struct {
+	Loads   map[string]int `json:"loads,omitempty"`
+	Pairs   map[string]int `json:"pairs,omitempty"`
+	Weights map[string]int `json:"weights,omitempty"`
+	Last    string         `json:"last,omitempty"`
+}
+
+// AssignmentAuditDTO Seed is a decimal string, uint64 does not fit JSON numbers.
+type AssignmentAuditDTO struct {
+	Action    string             `json:"action"`
+	Seed      string             `json:"seed"`
+	Steps     []SelectionStepDTO `json:"steps"`
+	CreatedAt time.Time          `json:"createdAt"`
+}
+
+type ExclusionDTO struct {
+	UserID string `json:"user_id"`
+	Reason string `json:"reason"`
+}
+
+// TeamCandidatesDTO eligible includes chosen reviewers of the team.
+type TeamCandidatesDTO struct {
+	TeamName string         `json:"team_name"`
+	Eligible []string       `json:"eligible"`
+	Excluded []ExclusionDTO `json:"excluded"`
+}
+
+type ChosenReviewerDTO struct {
+	UserID     string `json:"user_id"`
+	TeamName   string `json:"team_name,omitempty"`
+	Reason     string `json:"reason"`
+	IsFallback bool   `json:"is_fallback"`
+}
+
+type AssignmentDecisionDTO struct {
+	Action     string              `json:"action"`
+	Strategy   string              `json:"strategy,omitempty"`
+	Candidates []TeamCandidatesDTO `json:"candidates"`
+	Reviewers  []ChosenReviewerDTO `json:"reviewers"`
+	CreatedAt  time.Time           `json:"createdAt"`
+}
+
+type PRExplainResponse struct {
+	PullRequestID string                  `json:"pull_request_id"`
+	Decisions     []AssignmentDecisionDTO `json:"decisions"`
+}
+
+type PRAuditResponse struct {
+	PullRequestID string               `json:"pull_request_id"`
+	Audits        []AssignmentAuditDTO `json:"audits"`
+}
@@ -2,6 +2,7 @@ package http
 
 import (
 	"context"
+	"crypto/subtle"
 	"encoding/json"
 	"net/http"
 
@@ -14,30 +15,62 @@ import (
 type TeamsService interface {
 	CreateTeam(ctx context.Context, team domain.Team) (*domain.Team, error)
 	GetTeam(ctx context.Context, teamName string) (*domain.Team, error)
+	GetTeamSettings(ctx context.Context, teamName string) (domain.TeamSettings, error)
+	UpdateTeamSettings(ctx context.Context, teamName string, update domain.TeamSettingsUpdate) (domain.TeamSettings, error)
+	DeactivateTeamUsers(ctx context.Context, teamName string, userIDs []string) ([]domain.User, []domain.Reassignment, error)
+	GetCodeOwners(ctx context.Context, teamName string) ([]domain.CodeOwnersRule, error)
+	SetCodeOwners(ctx context.Context, teamName, text string) ([]domain.CodeOwnersRule, error)
+	SetUserTags(ctx context.Context, teamName, userID string, tags []string) (*domain.User, error)
+	SetUserSeniority(ctx context.Context, teamName, userID string, seniority domain.Seniority) (*domain.User, error)
 }
 
 type UsersService interface {
 	SetIsActive(ctx context.Context, userID string, isActive bool) (*domain.User, error)
-	GetUserReviews(ctx context.Context, userID string) ([]domain.PullRequest, error)
+	DeactivateUser(ctx context.Context, userID string) (*domain.User, []domain.Reassignment, error)
+	SetMaxOpenReviews(ctx context.Context, userID string, maxOpenReviews *int) (*domain.User, error)
+	SetWorkingHours(ctx context.Context, userID, timezone string, hours *domain.WorkingHours) (*domain.User, error)
+	GetUserReviews(ctx context.Context, userID string, filter domain.PullRequestFilter) (domain.PullRequestPage, error)
+	AddAbsence(ctx context.Context, absence domain.Absence) (domain.Absence, error)
+	ListAbsences(ctx context.Context, userID string) ([]domain.Absence, error)
+	CancelAbsence(ctx context.Context, absenceID int64) (domain.Absence, error)
 }
 
 type PullRequestsService interface {
-	CreatePullRequest(ctx context.Context, prID, prName, authorID string) (domain.PullRequest, error)
-	MergePullRequest(ctx context.Context, prID string) (domain.PullRequest, error)
-	ReassignReviewer(ctx context.Context, prID, oldUserID string) (domain.PullRequest, string, error)
+	CreatePullRequest(ctx context.Context, in domain.PullRequestCreate) (domain.PullRequest, error)
+	PreviewPullRequest(ctx context.Context, in domain.PullRequestCreate) (domain.PullRequestPreview, error)
+	MergePullRequest(ctx context.Context, prID string, override *domain.MergeOverride) (domain.PullRequest, error)
+	GetPullRequest(ctx context.Context, prID string) (domain.PullRequest, error)
+	ListPullRequests(ctx context.Context, filter domain.PullRequestFilter) (domain.PullRequestPage, error)
+	UpdatePullRequest(ctx context.Context, prID string, update domain.PullRequestUpdate) (domain.PullRequest, error)
+	ClosePullRequest(ctx context.Context, prID string) (domain.PullRequest, error)
+	ReopenPullRequest(ctx context.Context, prID string) (domain.PullRequest, error)
+	MarkPullRequestReady(ctx context.Context, prID string, changedFiles, requiredTags []string) (domain.PullRequest, error)
+	ReassignReviewer(ctx context.Context, prID, oldUserID string, force bool) (domain.PullRequest, string, error)
+	ReassignReviewerTo(ctx context.Context, prID, oldUserID, newUserID string, force bool) (domain.PullRequest, error)
+	AddReviewer(ctx context.Context, prID, userID string) (domain.PullRequest, error)
+	SubmitReview(ctx context.Context, prID, userID string, state domain.ReviewState) (domain.PullRequest, error)
+	RemoveReviewer(ctx context.Context, prID, userID string, force bool) (domain.PullRequest, error)
+	BackfillReviewers(ctx context.Context, prID string) ([]domain.Backfill, error)
+	GetAssignmentAudits(ctx context.Context, prID string) ([]domain.AssignmentAudit, error)
+	ReplayAssignment(ctx context.Context, audit domain.AssignmentAudit) ([][]string, error)
+	ExplainAssignment(ctx context.Context, prID string) ([]domain.AssignmentDecision, error)
 }
 
 type Handler struct {
 	teamsService TeamsService
 	usersService UsersService
 	prService    PullRequestsService
+	adminToken   string
 }
 
-func NewHandler(teams TeamsService, users UsersService, prs PullRequestsService) *Handler {
+// NewHandler adminToken is expected in the X-Admin-Token header of admin only requests
+// (merge override), empty token forbids them all.
+func NewHandler(teams TeamsService, users UsersService, prs PullRequestsService, adminToken string) *Handler {
 	return &Handler{
 		teamsService: teams,
 		usersService: users,
 		prService:    prs,
+		adminToken:   adminToken,
 	}
 }
 
@@ -51,17 +84,42 @@ func (h *Handler) Routes() http.Handler {
 	router.Route("/team", func(r chi.Router) {
 		r.Post("/add", h.handleTeamAdd)
 		r.Get("/get", h.handleTeamGet)
+		r.Get("/settings/get", h.handleTeamSettingsGet)
+		r.Post("/settings/set", h.handleTeamSettingsSet)
+		r.Get("/codeowners/get", h.handleTeamCodeOwnersGet)
+		r.Post("/codeowners/set", h.handleTeamCodeOwnersSet)
+		r.Post("/members/setTags", h.handleTeamSetMemberTags)
+		r.Post("/members/setSeniority", h.handleTeamSetMemberSeniority)
+		r.Post("/deactivateUsers", h.handleTeamDeactivateUsers)
 	})
 
 	router.Route("/users", func(r chi.Router) {
 		r.Post("/setIsActive", h.handleUserSetIsActive)
+		r.Post("/setCapacity", h.handleUserSetCapacity)
+		r.Post("/setWorkingHours", h.handleUserSetWorkingHours)
 		r.Get("/getReview", h.handleUsersGetReview)
+		r.Post("/absence/add", h.handleAbsenceAdd)
+		r.Get("/absence/list", h.handleAbsenceList)
+		r.Post("/absence/cancel", h.handleAbsenceCancel)
 	})
 
 	router.Route("/pullRequest", func(r chi.Router) {
 		r.Post("/create", h.handlePRCreate)
+		r.Get("/get", h.handlePRGet)
+		r.Get("/list", h.handlePRList)
+		r.Post("/preview", h.handlePRPreview)
 		r.Post("/merge", h.handlePRMerge)
+		r.Post("/update", h.handlePRUpdate)
+		r.Post("/close", h.handlePRClose)
+		r.Post("/reopen", h.handlePRReopen)
+		r.Post("/markReady", h.handlePRMarkReady)
 		r.Post("/reassign", h.handlePRReassign)
+		r.Post("/addReviewer", h.handlePRAddReviewer)
+		r.Post("/removeReviewer", h.handlePRRemoveReviewer)
+		r.Post("/review", h.handlePRReview)
+		r.Post("/backfill", h.handlePRBackfill)
+		r.Get("/audit", h.handlePRAudit)
+		r.Get("/explain", h.handlePRExplain)
 	})
 
 	router.Get("/health", func(w http.ResponseWriter, r *http.Request) {
@@ -84,6 +142,15 @@ func writeJSON(w http.ResponseWriter, status int, v any) {
 	_ = json.NewEncoder(w).Encode(v)
 }
 
+// isAdmin reports whether the request carries the admin token.
+func (h *Handler) isAdmin(r *http.Request) bool {
+	if h.adminToken == "" {
+		return false
+	}
+	token := r.Header.Get("X-Admin-Token")
+	return subtle.ConstantTimeCompare([]byte(token), []byte(h.adminToken)) == 1
+}
+
 func writeError(w http.ResponseWriter, err error) {
 	status, body := mappingDomainErrors(err)
 	writeJSON(w, status, body)
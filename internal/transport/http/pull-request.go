@@ -1,8 +1,15 @@
 package http
 
 import (
+	"context"
 	"encoding/json"
+	"errors"
+	"io"
 	"net/http"
+	"strconv"
+	"time"
+
+	"avito/internal/domain"
 )
 
 func (h *Handler) handlePRCreate(w http.ResponseWriter, r *http.Request) {
@@ -17,15 +24,57 @@ func (h *Handler) handlePRCreate(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	pr, err := h.prService.CreatePullRequest(r.Context(), req.ID, req.Name, req.Author)
+	pr, err := h.prService.CreatePullRequest(r.Context(), domain.PullRequestCreate{
+		ID:           req.ID,
+		Name:         req.Name,
+		AuthorID:     req.Author,
+		ChangedFiles: req.ChangedFiles,
+		RequiredTags: req.RequiredTags,
+		Draft:        req.Draft,
+	})
 	if err != nil {
 		writeError(w, err)
 		return
 	}
 
-	writeJSON(w, http.StatusCreated, PRCreateResponse{
-		PR: pullRequestToDto(pr),
+	writeJSON(w, http.StatusCreated, prCreateToDto(pr))
+}
+
+func (h *Handler) handlePRPreview(w http.ResponseWriter, r *http.Request) {
+	var req PRCreateRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeJSON(w, http.StatusBadRequest, ErrorResponse{
+			Error: errorBody{
+				Code:    "BAD_REQUEST",
+				Message: "invalid JSON",
+			},
+		})
+		return
+	}
+
+	if req.Author == "" {
+		writeJSON(w, http.StatusBadRequest, ErrorResponse{
+			Error: errorBody{
+				Code:    "BAD_REQUEST",
+				Message: "author_id is required",
+			},
+		})
+		return
+	}
+
+	preview, err := h.prService.PreviewPullRequest(r.Context(), domain.PullRequestCreate{
+		ID:           req.ID,
+		Name:         req.Name,
+		AuthorID:     req.Author,
+		ChangedFiles: req.ChangedFiles,
+		RequiredTags: req.RequiredTags,
 	})
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, prPreviewToDto(preview))
 }
 
 func (h *Handler) handlePRMerge(w http.ResponseWriter, r *http.Request) {
@@ -40,7 +89,30 @@ func (h *Handler) handlePRMerge(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	pr, err := h.prService.MergePullRequest(r.Context(), req.ID)
+	var override *domain.MergeOverride
+	if req.Override {
+		if !h.isAdmin(r) {
+			writeJSON(w, http.StatusForbidden, ErrorResponse{
+				Error: errorBody{
+					Code:    "FORBIDDEN",
+					Message: "override requires a valid X-Admin-Token",
+				},
+			})
+			return
+		}
+		if req.OverrideBy == "" {
+			writeJSON(w, http.StatusBadRequest, ErrorResponse{
+				Error: errorBody{
+					Code:    "BAD_REQUEST",
+					Message: "override_by is required with override",
+				},
+			})
+			return
+		}
+		override = &domain.MergeOverride{By: req.OverrideBy, Reason: req.OverrideReason}
+	}
+
+	pr, err := h.prService.MergePullRequest(r.Context(), req.ID, override)
 	if err != nil {
 		writeError(w, err)
 		return
@@ -51,6 +123,237 @@ func (h *Handler) handlePRMerge(w http.ResponseWriter, r *http.Request) {
 	})
 }
 
+func (h *Handler) handlePRGet(w http.ResponseWriter, r *http.Request) {
+	prID := r.URL.Query().Get("pull_request_id")
+	if prID == "" {
+		writeJSON(w, http.StatusBadRequest, ErrorResponse{
+			Error: errorBody{
+				Code:    "BAD_REQUEST",
+				Message: "pull_request_id is required",
+			},
+		})
+		return
+	}
+
+	pr, err := h.prService.GetPullRequest(r.Context(), prID)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, PRResponse{
+		PR: pullRequestToDto(pr),
+	})
+}
+
+// handlePRList status and label may be repeated, time ranges take RFC 3339 or YYYY-MM-DD.
+func (h *Handler) handlePRList(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+
+	filter := domain.PullRequestFilter{
+		AuthorID:   query.Get("author_id"),
+		ReviewerID: query.Get("reviewer_id"),
+		TeamName:   query.Get("team_name"),
+		Labels:     query["label"],
+	}
+	for _, status := range query["status"] {
+		filter.Statuses = append(filter.Statuses, domain.PullRequestStatus(status))
+	}
+
+	for _, param := range []struct {
+		name string
+		dst  **time.Time
+	}{
+		{"created_from", &filter.CreatedFrom},
+		{"created_to", &filter.CreatedTo},
+		{"merged_from", &filter.MergedFrom},
+		{"merged_to", &filter.MergedTo},
+	} {
+		t, err := parseTimeParam(query.Get(param.name))
+		if err != nil {
+			writeJSON(w, http.StatusBadRequest, ErrorResponse{
+				Error: errorBody{
+					Code:    "BAD_REQUEST",
+					Message: param.name + " must be RFC 3339 time or YYYY-MM-DD",
+				},
+			})
+			return
+		}
+		*param.dst = t
+	}
+
+	page, ok := parsePageParams(w, r)
+	if !ok {
+		return
+	}
+	filter.Limit, filter.After = page.limit, page.after
+
+	result, err := h.prService.ListPullRequests(r.Context(), filter)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	resp := PRListResponse{
+		PullRequests: make([]PullRequestDTO, 0, len(result.PullRequests)),
+		NextCursor:   encodeCursor(result.Next),
+	}
+	for _, pr := range result.PullRequests {
+		resp.PullRequests = append(resp.PullRequests, pullRequestToDto(pr))
+	}
+
+	writeJSON(w, http.StatusOK, resp)
+}
+
+type pageParams struct {
+	limit int
+	after *domain.PullRequestCursor
+}
+
+// parsePageParams reads limit and cursor query parameters, writes BAD_REQUEST when they are malformed.
+func parsePageParams(w http.ResponseWriter, r *http.Request) (pageParams, bool) {
+	var page pageParams
+
+	if raw := r.URL.Query().Get("limit"); raw != "" {
+		limit, err := strconv.Atoi(raw)
+		if err != nil {
+			writeJSON(w, http.StatusBadRequest, ErrorResponse{
+				Error: errorBody{
+					Code:    "BAD_REQUEST",
+					Message: "limit must be an integer",
+				},
+			})
+			return page, false
+		}
+		page.limit = limit
+	}
+
+	after, err := decodeCursor(r.URL.Query().Get("cursor"))
+	if err != nil {
+		writeJSON(w, http.StatusBadRequest, ErrorResponse{
+			Error: errorBody{
+				Code:    "BAD_REQUEST",
+				Message: "invalid cursor",
+			},
+		})
+		return page, false
+	}
+	page.after = after
+
+	return page, true
+}
+
+func (h *Handler) handlePRUpdate(w http.ResponseWriter, r *http.Request) {
+	var req PRUpdateRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeJSON(w, http.StatusBadRequest, ErrorResponse{
+			Error: errorBody{
+				Code:    "BAD_REQUEST",
+				Message: "invalid JSON",
+			},
+		})
+		return
+	}
+
+	if req.PullRequestID == "" {
+		writeJSON(w, http.StatusBadRequest, ErrorResponse{
+			Error: errorBody{
+				Code:    "BAD_REQUEST",
+				Message: "pull_request_id is required",
+			},
+		})
+		return
+	}
+
+	pr, err := h.prService.UpdatePullRequest(r.Context(), req.PullRequestID, domain.PullRequestUpdate{
+		Name:        req.Name,
+		Description: req.Description,
+		URL:         req.URL,
+		Labels:      req.Labels,
+	})
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, PRResponse{
+		PR: pullRequestToDto(pr),
+	})
+}
+
+func (h *Handler) handlePRClose(w http.ResponseWriter, r *http.Request) {
+	h.handlePRStatus(w, r, h.prService.ClosePullRequest)
+}
+
+func (h *Handler) handlePRReopen(w http.ResponseWriter, r *http.Request) {
+	h.handlePRStatus(w, r, h.prService.ReopenPullRequest)
+}
+
+// handlePRStatus handles requests changing only the status of PR.
+func (h *Handler) handlePRStatus(w http.ResponseWriter, r *http.Request, change func(ctx context.Context, prID string) (domain.PullRequest, error)) {
+	var req PRStatusRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeJSON(w, http.StatusBadRequest, ErrorResponse{
+			Error: errorBody{
+				Code:    "BAD_REQUEST",
+				Message: "invalid JSON",
+			},
+		})
+		return
+	}
+
+	if req.PullRequestID == "" {
+		writeJSON(w, http.StatusBadRequest, ErrorResponse{
+			Error: errorBody{
+				Code:    "BAD_REQUEST",
+				Message: "pull_request_id is required",
+			},
+		})
+		return
+	}
+
+	pr, err := change(r.Context(), req.PullRequestID)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, PRResponse{
+		PR: pullRequestToDto(pr),
+	})
+}
+
+func (h *Handler) handlePRMarkReady(w http.ResponseWriter, r *http.Request) {
+	var req PRMarkReadyRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeJSON(w, http.StatusBadRequest, ErrorResponse{
+			Error: errorBody{
+				Code:    "BAD_REQUEST",
+				Message: "invalid JSON",
+			},
+		})
+		return
+	}
+
+	if req.PullRequestID == "" {
+		writeJSON(w, http.StatusBadRequest, ErrorResponse{
+			Error: errorBody{
+				Code:    "BAD_REQUEST",
+				Message: "pull_request_id is required",
+			},
+		})
+		return
+	}
+
+	pr, err := h.prService.MarkPullRequestReady(r.Context(), req.PullRequestID, req.ChangedFiles, req.RequiredTags)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, prCreateToDto(pr))
+}
+
 func (h *Handler) handlePRReassign(w http.ResponseWriter, r *http.Request) {
 	var req PRReassignRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
@@ -63,7 +366,21 @@ func (h *Handler) handlePRReassign(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	pr, replacedBy, err := h.prService.ReassignReviewer(r.Context(), req.PullRequestID, req.OldUserID)
+	if req.NewUserID != "" {
+		pr, err := h.prService.ReassignReviewerTo(r.Context(), req.PullRequestID, req.OldUserID, req.NewUserID, req.Force)
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+
+		writeJSON(w, http.StatusOK, PRReassignResponse{
+			PR:         pullRequestToDto(pr),
+			ReplacedBy: req.NewUserID,
+		})
+		return
+	}
+
+	pr, replacedBy, err := h.prService.ReassignReviewer(r.Context(), req.PullRequestID, req.OldUserID, req.Force)
 	if err != nil {
 		writeError(w, err)
 		return
@@ -74,3 +391,214 @@ func (h *Handler) handlePRReassign(w http.ResponseWriter, r *http.Request) {
 		ReplacedBy: replacedBy,
 	})
 }
+
+func (h *Handler) handlePRAddReviewer(w http.ResponseWriter, r *http.Request) {
+	var req PRAddReviewerRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeJSON(w, http.StatusBadRequest, ErrorResponse{
+			Error: errorBody{
+				Code:    "BAD_REQUEST",
+				Message: "invalid JSON",
+			},
+		})
+		return
+	}
+
+	if req.PullRequestID == "" || req.UserID == "" {
+		writeJSON(w, http.StatusBadRequest, ErrorResponse{
+			Error: errorBody{
+				Code:    "BAD_REQUEST",
+				Message: "pull_request_id and user_id are required",
+			},
+		})
+		return
+	}
+
+	pr, err := h.prService.AddReviewer(r.Context(), req.PullRequestID, req.UserID)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, PRResponse{
+		PR: pullRequestToDto(pr),
+	})
+}
+
+func (h *Handler) handlePRRemoveReviewer(w http.ResponseWriter, r *http.Request) {
+	var req PRRemoveReviewerRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeJSON(w, http.StatusBadRequest, ErrorResponse{
+			Error: errorBody{
+				Code:    "BAD_REQUEST",
+				Message: "invalid JSON",
+			},
+		})
+		return
+	}
+
+	if req.PullRequestID == "" || req.UserID == "" {
+		writeJSON(w, http.StatusBadRequest, ErrorResponse{
+			Error: errorBody{
+				Code:    "BAD_REQUEST",
+				Message: "pull_request_id and user_id are required",
+			},
+		})
+		return
+	}
+
+	pr, err := h.prService.RemoveReviewer(r.Context(), req.PullRequestID, req.UserID, req.Force)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, PRResponse{
+		PR: pullRequestToDto(pr),
+	})
+}
+
+func (h *Handler) handlePRReview(w http.ResponseWriter, r *http.Request) {
+	var req PRReviewRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeJSON(w, http.StatusBadRequest, ErrorResponse{
+			Error: errorBody{
+				Code:    "BAD_REQUEST",
+				Message: "invalid JSON",
+			},
+		})
+		return
+	}
+
+	if req.PullRequestID == "" || req.UserID == "" || req.State == "" {
+		writeJSON(w, http.StatusBadRequest, ErrorResponse{
+			Error: errorBody{
+				Code:    "BAD_REQUEST",
+				Message: "pull_request_id, user_id and state are required",
+			},
+		})
+		return
+	}
+
+	pr, err := h.prService.SubmitReview(r.Context(), req.PullRequestID, req.UserID, domain.ReviewState(req.State))
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, PRResponse{
+		PR: pullRequestToDto(pr),
+	})
+}
+
+func (h *Handler) handlePRBackfill(w http.ResponseWriter, r *http.Request) {
+	var req PRBackfillRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil && !errors.Is(err, io.EOF) {
+		writeJSON(w, http.StatusBadRequest, ErrorResponse{
+			Error: errorBody{
+				Code:    "BAD_REQUEST",
+				Message: "invalid JSON",
+			},
+		})
+		return
+	}
+
+	backfills, err := h.prService.BackfillReviewers(r.Context(), req.PullRequestID)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	resp := PRBackfillResponse{
+		PullRequests: make([]PRBackfillDTO, 0, len(backfills)),
+	}
+	for _, backfill := range backfills {
+		resp.PullRequests = append(resp.PullRequests, backfillToDto(backfill))
+	}
+
+	writeJSON(w, http.StatusOK, resp)
+}
+
+func (h *Handler) handlePRAudit(w http.ResponseWriter, r *http.Request) {
+	prID := r.URL.Query().Get("pull_request_id")
+	if prID == "" {
+		writeJSON(w, http.StatusBadRequest, ErrorResponse{
+			Error: errorBody{
+				Code:    "BAD_REQUEST",
+				Message: "pull_request_id is required",
+			},
+		})
+		return
+	}
+
+	replay := false
+	if raw := r.URL.Query().Get("replay"); raw != "" {
+		var err error
+		replay, err = strconv.ParseBool(raw)
+		if err != nil {
+			writeJSON(w, http.StatusBadRequest, ErrorResponse{
+				Error: errorBody{
+					Code:    "BAD_REQUEST",
+					Message: "replay must be a boolean",
+				},
+			})
+			return
+		}
+	}
+
+	audits, err := h.prService.GetAssignmentAudits(r.Context(), prID)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	resp := PRAuditResponse{
+		PullRequestID: prID,
+		Audits:        make([]AssignmentAuditDTO, 0, len(audits)),
+	}
+	for _, audit := range audits {
+		dto := assignmentAuditToDto(audit)
+		if replay {
+			replayed, err := h.prService.ReplayAssignment(r.Context(), audit)
+			if err != nil {
+				writeError(w, err)
+				return
+			}
+			for i := range dto.Steps {
+				dto.Steps[i].Replayed = replayed[i]
+			}
+		}
+		resp.Audits = append(resp.Audits, dto)
+	}
+
+	writeJSON(w, http.StatusOK, resp)
+}
+
+func (h *Handler) handlePRExplain(w http.ResponseWriter, r *http.Request) {
+	prID := r.URL.Query().Get("pull_request_id")
+	if prID == "" {
+		writeJSON(w, http.StatusBadRequest, ErrorResponse{
+			Error: errorBody{
+				Code:    "BAD_REQUEST",
+				Message: "pull_request_id is required",
+			},
+		})
+		return
+	}
+
+	decisions, err := h.prService.ExplainAssignment(r.Context(), prID)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	resp := PRExplainResponse{
+		PullRequestID: prID,
+		Decisions:     make([]AssignmentDecisionDTO, 0, len(decisions)),
+	}
+	for _, decision := range decisions {
+		resp.Decisions = append(resp.Decisions, assignmentDecisionToDto(decision))
+	}
+
+	writeJSON(w, http.StatusOK, resp)
+}
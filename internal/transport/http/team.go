@@ -3,6 +3,8 @@ package http
 import (
 	"encoding/json"
 	"net/http"
+
+	"avito/internal/domain"
 )
 
 func (h *Handler) handleTeamAdd(w http.ResponseWriter, r *http.Request) {
@@ -49,3 +51,215 @@ func (h *Handler) handleTeamGet(w http.ResponseWriter, r *http.Request) {
 
 	writeJSON(w, http.StatusOK, teamToDto(team))
 }
+
+func (h *Handler) handleTeamSettingsGet(w http.ResponseWriter, r *http.Request) {
+	teamName := r.URL.Query().Get("team_name")
+	if teamName == "" {
+		writeJSON(w, http.StatusBadRequest, ErrorResponse{
+			Error: errorBody{
+				Code:    "BAD_REQUEST",
+				Message: "team_name is required",
+			},
+		})
+		return
+	}
+
+	settings, err := h.teamsService.GetTeamSettings(r.Context(), teamName)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, teamSettingsToDto(settings))
+}
+
+func (h *Handler) handleTeamSettingsSet(w http.ResponseWriter, r *http.Request) {
+	var req TeamSettingsSetRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeJSON(w, http.StatusBadRequest, ErrorResponse{
+			Error: errorBody{
+				Code:    "BAD_REQUEST",
+				Message: "invalid JSON",
+			},
+		})
+		return
+	}
+
+	if req.TeamName == "" {
+		writeJSON(w, http.StatusBadRequest, ErrorResponse{
+			Error: errorBody{
+				Code:    "BAD_REQUEST",
+				Message: "team_name is required",
+			},
+		})
+		return
+	}
+
+	settings, err := h.teamsService.UpdateTeamSettings(r.Context(), req.TeamName, teamSettingsUpdateFromDto(req))
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, TeamSettingsSetResponse{
+		Settings: teamSettingsToDto(settings),
+	})
+}
+
+func (h *Handler) handleTeamCodeOwnersGet(w http.ResponseWriter, r *http.Request) {
+	teamName := r.URL.Query().Get("team_name")
+	if teamName == "" {
+		writeJSON(w, http.StatusBadRequest, ErrorResponse{
+			Error: errorBody{
+				Code:    "BAD_REQUEST",
+				Message: "team_name is required",
+			},
+		})
+		return
+	}
+
+	rules, err := h.teamsService.GetCodeOwners(r.Context(), teamName)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, codeOwnersToDto(teamName, rules))
+}
+
+func (h *Handler) handleTeamCodeOwnersSet(w http.ResponseWriter, r *http.Request) {
+	var req TeamCodeOwnersSetRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeJSON(w, http.StatusBadRequest, ErrorResponse{
+			Error: errorBody{
+				Code:    "BAD_REQUEST",
+				Message: "invalid JSON",
+			},
+		})
+		return
+	}
+
+	if req.TeamName == "" {
+		writeJSON(w, http.StatusBadRequest, ErrorResponse{
+			Error: errorBody{
+				Code:    "BAD_REQUEST",
+				Message: "team_name is required",
+			},
+		})
+		return
+	}
+
+	rules, err := h.teamsService.SetCodeOwners(r.Context(), req.TeamName, req.CodeOwners)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, codeOwnersToDto(req.TeamName, rules))
+}
+
+func (h *Handler) handleTeamSetMemberTags(w http.ResponseWriter, r *http.Request) {
+	var req TeamSetMemberTagsRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeJSON(w, http.StatusBadRequest, ErrorResponse{
+			Error: errorBody{
+				Code:    "BAD_REQUEST",
+				Message: "invalid JSON",
+			},
+		})
+		return
+	}
+
+	if req.TeamName == "" || req.UserID == "" {
+		writeJSON(w, http.StatusBadRequest, ErrorResponse{
+			Error: errorBody{
+				Code:    "BAD_REQUEST",
+				Message: "team_name and user_id are required",
+			},
+		})
+		return
+	}
+
+	user, err := h.teamsService.SetUserTags(r.Context(), req.TeamName, req.UserID, req.Tags)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, TeamSetMemberTagsResponse{
+		User: userToDto(user),
+	})
+}
+
+func (h *Handler) handleTeamSetMemberSeniority(w http.ResponseWriter, r *http.Request) {
+	var req TeamSetMemberSeniorityRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeJSON(w, http.StatusBadRequest, ErrorResponse{
+			Error: errorBody{
+				Code:    "BAD_REQUEST",
+				Message: "invalid JSON",
+			},
+		})
+		return
+	}
+
+	if req.TeamName == "" || req.UserID == "" {
+		writeJSON(w, http.StatusBadRequest, ErrorResponse{
+			Error: errorBody{
+				Code:    "BAD_REQUEST",
+				Message: "team_name and user_id are required",
+			},
+		})
+		return
+	}
+
+	user, err := h.teamsService.SetUserSeniority(r.Context(), req.TeamName, req.UserID, domain.Seniority(req.Seniority))
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, TeamSetMemberSeniorityResponse{
+		User: userToDto(user),
+	})
+}
+
+func (h *Handler) handleTeamDeactivateUsers(w http.ResponseWriter, r *http.Request) {
+	var req TeamDeactivateUsersRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeJSON(w, http.StatusBadRequest, ErrorResponse{
+			Error: errorBody{
+				Code:    "BAD_REQUEST",
+				Message: "invalid JSON",
+			},
+		})
+		return
+	}
+
+	if req.TeamName == "" {
+		writeJSON(w, http.StatusBadRequest, ErrorResponse{
+			Error: errorBody{
+				Code:    "BAD_REQUEST",
+				Message: "team_name is required",
+			},
+		})
+		return
+	}
+
+	users, reassignments, err := h.teamsService.DeactivateTeamUsers(r.Context(), req.TeamName, req.UserIDs)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	resp := TeamDeactivateUsersResponse{
+		TeamName:      req.TeamName,
+		Users:         make([]UserDTO, 0, len(users)),
+		Reassignments: reassignmentsToDto(reassignments),
+	}
+	for _, user := range users {
+		resp.Users = append(resp.Users, userToDto(&user))
+	}
+
+	writeJSON(w, http.StatusOK, resp)
+}
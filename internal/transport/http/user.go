@@ -3,6 +3,9 @@ package http
 import (
 	"encoding/json"
 	"net/http"
+	"strconv"
+
+	"avito/internal/domain"
 )
 
 func (h *Handler) handleUserSetIsActive(w http.ResponseWriter, r *http.Request) {
@@ -17,6 +20,35 @@ func (h *Handler) handleUserSetIsActive(w http.ResponseWriter, r *http.Request)
 		return
 	}
 
+	reassign := false
+	if raw := r.URL.Query().Get("reassign"); raw != "" {
+		var err error
+		reassign, err = strconv.ParseBool(raw)
+		if err != nil {
+			writeJSON(w, http.StatusBadRequest, ErrorResponse{
+				Error: errorBody{
+					Code:    "BAD_REQUEST",
+					Message: "reassign must be a boolean",
+				},
+			})
+			return
+		}
+	}
+
+	if reassign && !req.IsActive {
+		user, reassignments, err := h.usersService.DeactivateUser(r.Context(), req.UserID)
+		if err != nil {
+			writeError(w, err)
+			return
+		}
+
+		writeJSON(w, http.StatusOK, UserSetIsActiveResponse{
+			User:          userToDto(user),
+			Reassignments: reassignmentsToDto(reassignments),
+		})
+		return
+	}
+
 	user, err := h.usersService.SetIsActive(r.Context(), req.UserID, req.IsActive)
 	if err != nil {
 		writeError(w, err)
@@ -28,6 +60,78 @@ func (h *Handler) handleUserSetIsActive(w http.ResponseWriter, r *http.Request)
 	})
 }
 
+func (h *Handler) handleUserSetCapacity(w http.ResponseWriter, r *http.Request) {
+	var req UserSetCapacityRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeJSON(w, http.StatusBadRequest, ErrorResponse{
+			Error: errorBody{
+				Code:    "BAD_REQUEST",
+				Message: "invalid JSON",
+			},
+		})
+		return
+	}
+
+	user, err := h.usersService.SetMaxOpenReviews(r.Context(), req.UserID, req.MaxOpenReviews)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, UserSetCapacityResponse{
+		User: userToDto(user),
+	})
+}
+
+func (h *Handler) handleUserSetWorkingHours(w http.ResponseWriter, r *http.Request) {
+	var req UserSetWorkingHoursRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		writeJSON(w, http.StatusBadRequest, ErrorResponse{
+			Error: errorBody{
+				Code:    "BAD_REQUEST",
+				Message: "invalid JSON",
+			},
+		})
+		return
+	}
+
+	if (req.WorkStart == nil) != (req.WorkEnd == nil) {
+		writeJSON(w, http.StatusBadRequest, ErrorResponse{
+			Error: errorBody{
+				Code:    "BAD_REQUEST",
+				Message: "work_start and work_end must be set together",
+			},
+		})
+		return
+	}
+
+	var hours *domain.WorkingHours
+	if req.WorkStart != nil {
+		start, errStart := parseClock(*req.WorkStart)
+		end, errEnd := parseClock(*req.WorkEnd)
+		if errStart != nil || errEnd != nil {
+			writeJSON(w, http.StatusBadRequest, ErrorResponse{
+				Error: errorBody{
+					Code:    "BAD_REQUEST",
+					Message: "work_start and work_end must be HH:MM",
+				},
+			})
+			return
+		}
+		hours = &domain.WorkingHours{Start: start, End: end}
+	}
+
+	user, err := h.usersService.SetWorkingHours(r.Context(), req.UserID, req.Timezone, hours)
+	if err != nil {
+		writeError(w, err)
+		return
+	}
+
+	writeJSON(w, http.StatusOK, UserSetWorkingHoursResponse{
+		User: userToDto(user),
+	})
+}
+
 func (h *Handler) handleUsersGetReview(w http.ResponseWriter, r *http.Request) {
 	userID := r.URL.Query().Get("user_id")
 	if userID == "" {
@@ -40,7 +144,20 @@ func (h *Handler) handleUsersGetReview(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	prs, err := h.usersService.GetUserReviews(r.Context(), userID)
+	filter := domain.PullRequestFilter{
+		Labels: r.URL.Query()["label"],
+	}
+	for _, status := range r.URL.Query()["status"] {
+		filter.Statuses = append(filter.Statuses, domain.PullRequestStatus(status))
+	}
+
+	page, ok := parsePageParams(w, r)
+	if !ok {
+		return
+	}
+	filter.Limit, filter.After = page.limit, page.after
+
+	result, err := h.usersService.GetUserReviews(r.Context(), userID, filter)
 	if err != nil {
 		writeError(w, err)
 		return
@@ -48,10 +165,11 @@ func (h *Handler) handleUsersGetReview(w http.ResponseWriter, r *http.Request) {
 
 	resp := UsersGetReviewResponse{
 		UserID:       userID,
-		PullRequests: make([]PullRequestShortDTO, 0, len(prs)),
+		PullRequests: make([]PullRequestShortDTO, 0, len(result.PullRequests)),
+		NextCursor:   encodeCursor(result.Next),
 	}
 
-	for _, pr := range prs {
+	for _, pr := range result.PullRequests {
 		resp.PullRequests = append(resp.PullRequests, pullRequestShortToDto(pr))
 	}
 
@@ -0,0 +1,11 @@
+CREATE TABLE team_settings (
+    team_name      text PRIMARY KEY REFERENCES teams(name) ON DELETE CASCADE,
+    min_reviewers  integer NOT NULL DEFAULT 2,
+    max_reviewers  integer NOT NULL DEFAULT 2,
+    strategy       text,
+    allow_partial  boolean NOT NULL DEFAULT true,
+    CHECK (min_reviewers >= 0 AND min_reviewers <= max_reviewers)
+);
+
+INSERT INTO team_settings (team_name)
+SELECT name FROM teams;
@@ -0,0 +1,5 @@
+ALTER TABLE pull_request_reviewers
+    DROP COLUMN IF EXISTS is_fallback;
+
+ALTER TABLE team_settings
+    DROP COLUMN IF EXISTS backup_teams;
@@ -0,0 +1,5 @@
+ALTER TABLE team_settings
+    ADD COLUMN backup_teams text[] NOT NULL DEFAULT '{}';
+
+ALTER TABLE pull_request_reviewers
+    ADD COLUMN is_fallback boolean NOT NULL DEFAULT false;
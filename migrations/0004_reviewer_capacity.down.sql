@@ -0,0 +1,8 @@
+DROP INDEX IF EXISTS idx_pull_requests_status_created_at;
+
+UPDATE pull_requests
+   SET status = 'OPEN'
+ WHERE status = 'NEEDS_REVIEWERS';
+
+ALTER TABLE users
+    DROP COLUMN IF EXISTS max_open_reviews;
@@ -0,0 +1,5 @@
+ALTER TABLE users
+    ADD COLUMN max_open_reviews integer CHECK (max_open_reviews >= 0);
+
+CREATE INDEX IF NOT EXISTS idx_pull_requests_status_created_at
+    ON pull_requests (status, created_at);
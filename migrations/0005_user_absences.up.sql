@@ -0,0 +1,13 @@
+CREATE TABLE user_absences (
+    id           bigserial PRIMARY KEY,
+    user_id      text NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    start_date   date NOT NULL,
+    end_date     date NOT NULL,
+    reason       text NOT NULL DEFAULT '',
+    created_at   timestamptz NOT NULL,
+    cancelled_at timestamptz,
+    CHECK (start_date <= end_date)
+);
+
+CREATE INDEX IF NOT EXISTS idx_user_absences_user_id_end_date
+    ON user_absences (user_id, end_date);
@@ -0,0 +1,8 @@
+ALTER TABLE users
+    ADD COLUMN timezone   text,
+    ADD COLUMN work_start smallint CHECK (work_start >= 0 AND work_start < 1440),
+    ADD COLUMN work_end   smallint CHECK (work_end >= 0 AND work_end < 1440),
+    ADD CHECK ((work_start IS NULL) = (work_end IS NULL));
+
+ALTER TABLE team_settings
+    ADD COLUMN prefer_working_hours boolean NOT NULL DEFAULT false;
@@ -0,0 +1,5 @@
+CREATE TABLE team_code_owners (
+    team_name  text PRIMARY KEY REFERENCES teams(name) ON DELETE CASCADE,
+    rules      text NOT NULL,
+    updated_at timestamptz NOT NULL DEFAULT now()
+);
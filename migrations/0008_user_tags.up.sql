@@ -0,0 +1,2 @@
+ALTER TABLE users
+    ADD COLUMN tags text[] NOT NULL DEFAULT '{}';
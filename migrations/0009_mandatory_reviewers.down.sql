@@ -0,0 +1,5 @@
+ALTER TABLE pull_request_reviewers
+    DROP COLUMN IF EXISTS is_mandatory;
+
+ALTER TABLE team_settings
+    DROP COLUMN IF EXISTS mandatory_reviewers;
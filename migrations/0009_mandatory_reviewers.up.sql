@@ -0,0 +1,5 @@
+ALTER TABLE team_settings
+    ADD COLUMN mandatory_reviewers text[] NOT NULL DEFAULT '{}';
+
+ALTER TABLE pull_request_reviewers
+    ADD COLUMN is_mandatory boolean NOT NULL DEFAULT false;
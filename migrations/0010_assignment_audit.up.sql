@@ -0,0 +1,11 @@
+CREATE TABLE assignment_audit (
+    id              bigserial PRIMARY KEY,
+    pull_request_id text NOT NULL REFERENCES pull_requests(id) ON DELETE CASCADE,
+    action          text NOT NULL,
+    seed            bigint NOT NULL,
+    steps           jsonb NOT NULL,
+    created_at      timestamptz NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_assignment_audit_pull_request_id
+    ON assignment_audit (pull_request_id, id);
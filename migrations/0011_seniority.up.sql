@@ -0,0 +1,6 @@
+ALTER TABLE users
+    ADD COLUMN seniority text CHECK (seniority IN ('JUNIOR', 'MIDDLE', 'SENIOR', 'LEAD'));
+
+ALTER TABLE team_settings
+    ADD COLUMN require_senior    boolean NOT NULL DEFAULT false,
+    ADD COLUMN juniors_not_alone boolean NOT NULL DEFAULT false;
@@ -0,0 +1,12 @@
+CREATE TABLE assignment_decisions (
+    id              bigserial PRIMARY KEY,
+    pull_request_id text NOT NULL REFERENCES pull_requests(id) ON DELETE CASCADE,
+    action          text NOT NULL,
+    strategy        text,
+    teams           jsonb NOT NULL,
+    reviewers       jsonb NOT NULL,
+    created_at      timestamptz NOT NULL
+);
+
+CREATE INDEX IF NOT EXISTS idx_assignment_decisions_pull_request_id
+    ON assignment_decisions (pull_request_id, id);
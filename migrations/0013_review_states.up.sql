@@ -0,0 +1,4 @@
+ALTER TABLE pull_request_reviewers
+    ADD COLUMN state            text NOT NULL DEFAULT 'PENDING'
+        CHECK (state IN ('PENDING', 'APPROVED', 'CHANGES_REQUESTED', 'COMMENTED')),
+    ADD COLUMN state_updated_at timestamptz;
@@ -0,0 +1,6 @@
+ALTER TABLE pull_requests
+    DROP COLUMN IF EXISTS merge_override_reason,
+    DROP COLUMN IF EXISTS merge_override_by;
+
+ALTER TABLE team_settings
+    DROP COLUMN IF EXISTS required_approvals;
@@ -0,0 +1,6 @@
+ALTER TABLE team_settings
+    ADD COLUMN required_approvals integer NOT NULL DEFAULT 0 CHECK (required_approvals >= 0);
+
+ALTER TABLE pull_requests
+    ADD COLUMN merge_override_by     text,
+    ADD COLUMN merge_override_reason text;
@@ -0,0 +1,7 @@
+UPDATE pull_requests
+   SET status = 'OPEN'
+ WHERE status IN ('DRAFT', 'CLOSED');
+
+ALTER TABLE pull_requests
+    DROP COLUMN IF EXISTS closed_from,
+    DROP COLUMN IF EXISTS closed_at;
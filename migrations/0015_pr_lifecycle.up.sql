@@ -0,0 +1,3 @@
+ALTER TABLE pull_requests
+    ADD COLUMN closed_at   timestamptz,
+    ADD COLUMN closed_from text;
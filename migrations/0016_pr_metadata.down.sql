@@ -0,0 +1,5 @@
+DROP TABLE IF EXISTS pull_request_labels;
+
+ALTER TABLE pull_requests
+    DROP COLUMN IF EXISTS url,
+    DROP COLUMN IF EXISTS description;
@@ -0,0 +1,12 @@
+ALTER TABLE pull_requests
+    ADD COLUMN description text NOT NULL DEFAULT '',
+    ADD COLUMN url         text NOT NULL DEFAULT '';
+
+CREATE TABLE pull_request_labels (
+    pull_request_id text NOT NULL REFERENCES pull_requests(id) ON DELETE CASCADE,
+    label           text NOT NULL,
+    PRIMARY KEY (pull_request_id, label)
+);
+
+CREATE INDEX IF NOT EXISTS idx_pull_request_labels_label
+    ON pull_request_labels (label, pull_request_id);
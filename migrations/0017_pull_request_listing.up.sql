@@ -0,0 +1,14 @@
+CREATE INDEX IF NOT EXISTS idx_pull_requests_created_at_id
+    ON pull_requests (created_at, id);
+
+CREATE INDEX IF NOT EXISTS idx_pull_requests_status_created_at_id
+    ON pull_requests (status, created_at, id);
+
+DROP INDEX IF EXISTS idx_pull_requests_status_created_at;
+
+CREATE INDEX IF NOT EXISTS idx_pull_requests_author_id_created_at_id
+    ON pull_requests (author_id, created_at, id);
+
+CREATE INDEX IF NOT EXISTS idx_pull_requests_merged_at
+    ON pull_requests (merged_at)
+    WHERE merged_at IS NOT NULL;
@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS idx_pull_requests_status_last_backfill_attempt_at;
+
+ALTER TABLE pull_requests
+    DROP COLUMN IF EXISTS last_backfill_attempt_at;
@@ -0,0 +1,5 @@
+ALTER TABLE pull_requests
+    ADD COLUMN last_backfill_attempt_at timestamptz;
+
+CREATE INDEX IF NOT EXISTS idx_pull_requests_status_last_backfill_attempt_at
+    ON pull_requests (status, last_backfill_attempt_at NULLS FIRST, created_at, id);